@@ -0,0 +1,107 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"syscall"
+
+	"github.com/ftphikari/wlaunchpad/internal/compositor"
+	"github.com/ftphikari/wlaunchpad/internal/ipc"
+)
+
+// Adds a key binding toggling the launcher to the compositor's config file,
+// returns the exit status
+func bindKey(args []string) int {
+	fs := flag.NewFlagSet("bind-key", flag.ExitOnError)
+	kind := fs.String("compositor", compositor.DetectConfig(), `config file to add the binding to: "sway" or "hyprland"`)
+	force := fs.Bool("force", false, "add the binding even if no daemon is found")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: wlaunchpad bind-key [flags] KEY, e.g. SUPER or SUPER+D")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+	if *kind == "" {
+		fmt.Fprintln(os.Stderr, "Neither sway nor Hyprland is running, pick one with -compositor")
+		return 1
+	}
+
+	line, err := compositor.Binding(*kind, fs.Arg(0), launcherCommand())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	path := compositor.ConfigPath(*kind)
+	if settings.DryRun {
+		fmt.Printf("%s: %s\n", path, line)
+		return 0
+	}
+
+	contents, _ := ioutil.ReadFile(path)
+	if !*force && !daemonFound(contents) {
+		fmt.Fprintln(os.Stderr, "No wlaunchpad daemon is running, nor started by the compositor or a systemd user service,")
+		fmt.Fprintln(os.Stderr, "so every key press would start the launcher from scratch. Add `exec wlaunchpad -d -n`")
+		fmt.Fprintln(os.Stderr, "to the config file first, or pass -force.")
+		return 1
+	}
+
+	added, err := compositor.AddBinding(path, line)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Couldn't add the binding to %s: %s\n", path, err)
+		return 1
+	}
+	if !added {
+		fmt.Printf("%s has the binding already\n", path)
+		return 0
+	}
+	fmt.Printf("Added %q to %s, the previous version is in %s.bak\n", line, path, path)
+	if *kind == compositor.ConfigSway {
+		fmt.Println("Run `swaymsg reload` to use it.")
+	}
+	return 0
+}
+
+// Returns the command the binding runs: the plain name when the launcher is
+// found in PATH, so that the line survives reinstalls elsewhere
+func launcherCommand() string {
+	if _, err := exec.LookPath("wlaunchpad"); err == nil {
+		return "wlaunchpad"
+	}
+	if path, err := os.Executable(); err == nil {
+		return path
+	}
+	return "wlaunchpad"
+}
+
+// Reports whether a daemon runs, or gets started by the compositor config
+// contents or a systemd user service
+func daemonFound(contents []byte) bool {
+	if pid, err := ipc.LockFilePid(ipc.LockFilePath()); err == nil && syscall.Kill(pid, 0) == nil {
+		return true
+	}
+	if compositor.Autostarts(contents) {
+		return true
+	}
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if configHome == "" {
+		configHome = filepath.Join(os.Getenv("HOME"), ".config")
+	}
+	for _, dir := range []string{
+		filepath.Join(configHome, "systemd", "user"),
+		filepath.Join(os.Getenv("HOME"), ".local", "share", "systemd", "user"),
+		"/etc/systemd/user",
+		"/usr/lib/systemd/user",
+	} {
+		if _, err := os.Stat(filepath.Join(dir, "wlaunchpad.service")); err == nil {
+			return true
+		}
+	}
+	return false
+}
@@ -1,4 +1,8 @@
 #!/bin/sh
 name=$(basename $(pwd))
+pkg=github.com/ftphikari/wlaunchpad/internal/config
+version=$(git describe --tags 2>/dev/null || echo dev)
+commit=$(git rev-parse --short HEAD 2>/dev/null || echo unknown)
+date=$(date -u +%Y-%m-%d)
 go mod tidy
-go build -o ${name}.elf -ldflags '-s -w' -trimpath .
+go build -o ${name}.elf -ldflags "-s -w -X ${pkg}.Version=${version} -X ${pkg}.Commit=${commit} -X ${pkg}.BuildDate=${date}" -trimpath .
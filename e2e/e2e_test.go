@@ -0,0 +1,234 @@
+//go:build e2e
+// +build e2e
+
+// Package e2e runs wlaunchpad under a headless sway session, types into it
+// with wtype and checks what it shows (from its debug log) and what it
+// launches (with a recorder script as the Exec of the test entries).
+//
+// Needs sway, wtype and the wlaunchpad build dependencies, but no display or
+// input devices, so it runs on a headless machine. No CI job runs it yet:
+//
+//	go test -tags e2e ./e2e
+package e2e
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const timeout = 10 * time.Second
+
+// Thread-safe buffer collecting the output of a process
+type output struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (o *output) Write(p []byte) (int, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.buf.Write(p)
+}
+
+func (o *output) String() string {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.buf.String()
+}
+
+type session struct {
+	t       *testing.T
+	dir     string
+	env     []string
+	binary  string
+	records string
+}
+
+func newSession(t *testing.T) *session {
+	for _, tool := range []string{"sway", "wtype"} {
+		if _, err := exec.LookPath(tool); err != nil {
+			t.Skipf("%s not installed", tool)
+		}
+	}
+
+	s := &session{t: t, dir: t.TempDir()}
+	s.binary = filepath.Join(s.dir, "wlaunchpad")
+	build := exec.Command("go", "build", "-o", s.binary, "..")
+	if out, err := build.CombinedOutput(); err != nil {
+		t.Fatalf("build failed: %s\n%s", err, out)
+	}
+
+	runtimeDir := filepath.Join(s.dir, "runtime")
+	os.Mkdir(runtimeDir, 0700)
+	s.env = append(os.Environ(),
+		"XDG_RUNTIME_DIR="+runtimeDir,
+		"TMPDIR="+runtimeDir,
+		"XDG_DATA_HOME="+filepath.Join(s.dir, "data"),
+		"XDG_DATA_DIRS="+filepath.Join(s.dir, "system"),
+		"XDG_CONFIG_HOME="+filepath.Join(s.dir, "config"),
+		"XDG_STATE_HOME="+filepath.Join(s.dir, "state"),
+		"WLR_BACKENDS=headless",
+		"WLR_LIBINPUT_NO_DEVICES=1",
+		"WLR_RENDERER=pixman",
+	)
+
+	// Every launch appends the arguments to the records file. The entries
+	// run binaries of their own, as Exec lines are searched too.
+	s.records = filepath.Join(s.dir, "records")
+	script := fmt.Sprintf("#!/bin/sh\necho \"$*\" >> %s\n", s.records)
+	for _, name := range []string{"recorder", "other-app"} {
+		if err := os.WriteFile(filepath.Join(s.dir, name), []byte(script), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s.addEntry("recorder.desktop", fmt.Sprintf("[Desktop Entry]\nType=Application\nName=Recorder App\nExec=%s recorded-arg %%U\n", filepath.Join(s.dir, "recorder")))
+	s.addEntry("other.desktop", fmt.Sprintf("[Desktop Entry]\nType=Application\nName=Other Thing\nExec=%s other\n", filepath.Join(s.dir, "other-app")))
+
+	sway := exec.Command("sway", "-c", "testdata/sway.config")
+	sway.Env = s.env
+	swayOutput := &output{}
+	sway.Stderr = swayOutput
+	if err := sway.Start(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		sway.Process.Kill()
+		sway.Wait()
+	})
+
+	socket := ""
+	s.waitFor("wayland socket", func() bool {
+		sockets, _ := filepath.Glob(filepath.Join(runtimeDir, "wayland-*"))
+		for _, path := range sockets {
+			if !strings.HasSuffix(path, ".lock") {
+				socket = filepath.Base(path)
+				return true
+			}
+		}
+		return false
+	})
+	s.env = append(s.env, "WAYLAND_DISPLAY="+socket)
+	return s
+}
+
+func (s *session) addEntry(id, contents string) {
+	dir := filepath.Join(s.dir, "data", "applications")
+	os.MkdirAll(dir, 0755)
+	if err := os.WriteFile(filepath.Join(dir, id), []byte(contents), 0644); err != nil {
+		s.t.Fatal(err)
+	}
+}
+
+// Starts wlaunchpad in debug mode, returns its log
+func (s *session) start(args ...string) (*exec.Cmd, *output) {
+	cmd := exec.Command(s.binary, append([]string{"-debug"}, args...)...)
+	cmd.Env = s.env
+	log := &output{}
+	cmd.Stdout = log
+	cmd.Stderr = log
+	if err := cmd.Start(); err != nil {
+		s.t.Fatal(err)
+	}
+	s.t.Cleanup(func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	})
+	s.waitFor("UI", func() bool {
+		return strings.Contains(log.String(), "UI created")
+	})
+	return cmd, log
+}
+
+func (s *session) wtype(args ...string) {
+	cmd := exec.Command("wtype", args...)
+	cmd.Env = s.env
+	if out, err := cmd.CombinedOutput(); err != nil {
+		s.t.Fatalf("wtype %q failed: %s\n%s", args, err, out)
+	}
+}
+
+// Waits for the command to exit, returns false if it didn't in time
+func (s *session) waitExit(cmd *exec.Cmd) bool {
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+	select {
+	case <-done:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+func (s *session) waitFor(what string, cond func() bool) {
+	deadline := time.Now().Add(timeout)
+	for !cond() {
+		if time.Now().After(deadline) {
+			s.t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+func TestSearchAndLaunch(t *testing.T) {
+	s := newSession(t)
+	cmd, log := s.start()
+
+	s.wtype("recorder")
+	s.waitFor("search results", func() bool {
+		return strings.Contains(log.String(), "Grid: 1 buttons shown")
+	})
+
+	s.wtype("-k", "Return")
+	s.waitFor("launch", func() bool {
+		records, _ := os.ReadFile(s.records)
+		return len(records) > 0
+	})
+
+	records, _ := os.ReadFile(s.records)
+	if got := strings.TrimSpace(string(records)); got != "recorded-arg" {
+		t.Errorf("expected the launched app to get \"recorded-arg\", got %q", got)
+	}
+
+	// Without -d, launching quits
+	if !s.waitExit(cmd) {
+		t.Error("wlaunchpad didn't quit after launching")
+	}
+}
+
+func TestEmptyQueryShowsAllEntries(t *testing.T) {
+	s := newSession(t)
+	_, log := s.start()
+
+	// the entries are scanned once the UI is up
+	s.waitFor("2 buttons in the grid", func() bool {
+		return strings.Contains(log.String(), "Grid: 2 buttons shown")
+	})
+}
+
+func TestDryRun(t *testing.T) {
+	s := newSession(t)
+	cmd, log := s.start("-dry-run")
+
+	s.wtype("recorder")
+	s.waitFor("search results", func() bool {
+		return strings.Contains(log.String(), "Grid: 1 buttons shown")
+	})
+	s.wtype("-k", "Return")
+	if !s.waitExit(cmd) {
+		t.Fatal("wlaunchpad didn't quit after the dry run")
+	}
+
+	if !strings.Contains(log.String(), `"recorded-arg"]`) {
+		t.Errorf("expected the resolved argv in the output, got:\n%s", log.String())
+	}
+	if _, err := os.Stat(s.records); err == nil {
+		t.Error("dry run launched the app")
+	}
+}
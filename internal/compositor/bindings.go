@@ -0,0 +1,160 @@
+package compositor
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ftphikari/wlaunchpad/internal/config"
+)
+
+// Compositors whose config files we know how to add key bindings to
+const (
+	ConfigSway     = "sway"
+	ConfigHyprland = "hyprland"
+)
+
+// DetectConfig returns the kind of config file of the running compositor, ""
+// if it isn't one we know
+func DetectConfig() string {
+	switch {
+	case os.Getenv("SWAYSOCK") != "":
+		return ConfigSway
+	case os.Getenv("HYPRLAND_INSTANCE_SIGNATURE") != "":
+		return ConfigHyprland
+	}
+	return ""
+}
+
+// ConfigPath returns the config file of the compositor, the first one found
+// where it looks for it, else where it would be created
+func ConfigPath(kind string) string {
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if configHome == "" {
+		configHome = filepath.Join(os.Getenv("HOME"), ".config")
+	}
+	var candidates []string
+	switch kind {
+	case ConfigSway:
+		candidates = []string{
+			filepath.Join(os.Getenv("HOME"), ".sway", "config"),
+			filepath.Join(configHome, "sway", "config"),
+		}
+	case ConfigHyprland:
+		candidates = []string{filepath.Join(configHome, "hypr", "hyprland.conf")}
+	}
+	for _, path := range candidates {
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return candidates[len(candidates)-1]
+}
+
+// Modifiers by the names people type: sway's name, Hyprland's name and the
+// key of the modifier pressed alone, in sway's and Hyprland's spelling
+var modifiers = map[string][4]string{
+	"SUPER":   {"Mod4", "SUPER", "Super_L", "SUPER_L"},
+	"MOD4":    {"Mod4", "SUPER", "Super_L", "SUPER_L"},
+	"LOGO":    {"Mod4", "SUPER", "Super_L", "SUPER_L"},
+	"WIN":     {"Mod4", "SUPER", "Super_L", "SUPER_L"},
+	"ALT":     {"Mod1", "ALT", "Alt_L", "ALT_L"},
+	"MOD1":    {"Mod1", "ALT", "Alt_L", "ALT_L"},
+	"CTRL":    {"Ctrl", "CTRL", "Control_L", "CONTROL_L"},
+	"CONTROL": {"Ctrl", "CTRL", "Control_L", "CONTROL_L"},
+	"SHIFT":   {"Shift", "SHIFT", "Shift_L", "SHIFT_L"},
+}
+
+// Binding returns the config line of the compositor running command on key:
+// modifiers and a key joined with "+", like "SUPER+D", or a modifier alone,
+// like "SUPER", which then fires once it is released
+func Binding(kind, key, command string) (string, error) {
+	parts := strings.Split(key, "+")
+	var swayMods, hyprMods []string
+	for _, part := range parts[:len(parts)-1] {
+		mod, ok := modifiers[strings.ToUpper(strings.TrimSpace(part))]
+		if !ok {
+			return "", fmt.Errorf("unknown modifier %q", part)
+		}
+		swayMods = append(swayMods, mod[0])
+		hyprMods = append(hyprMods, mod[1])
+	}
+	last := strings.TrimSpace(parts[len(parts)-1])
+	if last == "" {
+		return "", fmt.Errorf("no key in %q", key)
+	}
+
+	switch kind {
+	case ConfigSway:
+		if mod, ok := modifiers[strings.ToUpper(last)]; ok && len(parts) == 1 {
+			return fmt.Sprintf("bindsym --release %s exec %s", mod[2], command), nil
+		}
+		if len(last) == 1 {
+			// "D" would be shift+d
+			last = strings.ToLower(last)
+		}
+		return fmt.Sprintf("bindsym %s exec %s", strings.Join(append(swayMods, last), "+"), command), nil
+	case ConfigHyprland:
+		if mod, ok := modifiers[strings.ToUpper(last)]; ok && len(parts) == 1 {
+			return fmt.Sprintf("bindr = %s, %s, exec, %s", mod[1], mod[3], command), nil
+		}
+		return fmt.Sprintf("bind = %s, %s, exec, %s", strings.Join(hyprMods, " "), strings.ToUpper(last), command), nil
+	}
+	return "", fmt.Errorf("unknown compositor %q", kind)
+}
+
+// Autostarts reports whether the config file contents start the launcher in
+// daemon mode
+func Autostarts(contents []byte) bool {
+	for _, line := range strings.Split(string(contents), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || !strings.HasPrefix(fields[0], "exec") {
+			continue
+		}
+		if strings.Contains(line, "wlaunchpad") && strings.Contains(line, " -d") {
+			return true
+		}
+	}
+	return false
+}
+
+// AddBinding appends line to the config file at path, after copying it to
+// <path>.bak. When the file has the line already nothing is written and false
+// is returned. Symlinked config files, as kept in dotfile repositories, are
+// written through.
+func AddBinding(path, line string) (bool, error) {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+	contents, err := ioutil.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return false, err
+	}
+	for _, l := range strings.Split(string(contents), "\n") {
+		if strings.Join(strings.Fields(l), " ") == line {
+			return false, nil
+		}
+	}
+
+	perm := os.FileMode(0644)
+	if info, err := os.Stat(path); err == nil {
+		perm = info.Mode().Perm()
+		if err := ioutil.WriteFile(path+".bak", contents, perm); err != nil {
+			return false, fmt.Errorf("couldn't back up %s: %s", path, err)
+		}
+	}
+
+	var b bytes.Buffer
+	b.Write(contents)
+	if len(contents) > 0 && !bytes.HasSuffix(contents, []byte("\n")) {
+		b.WriteByte('\n')
+	}
+	fmt.Fprintf(&b, "\n# toggle wlaunchpad\n%s\n", line)
+	if err := config.WriteFile(path, b.Bytes(), perm); err != nil {
+		return false, err
+	}
+	return true, nil
+}
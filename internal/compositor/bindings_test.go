@@ -0,0 +1,95 @@
+package compositor
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBinding(t *testing.T) {
+	for _, tt := range []struct {
+		kind, key, want string
+	}{
+		{ConfigSway, "SUPER", "bindsym --release Super_L exec wlaunchpad"},
+		{ConfigSway, "super+D", "bindsym Mod4+d exec wlaunchpad"},
+		{ConfigSway, "Ctrl+Alt+space", "bindsym Ctrl+Mod1+space exec wlaunchpad"},
+		{ConfigHyprland, "SUPER", "bindr = SUPER, SUPER_L, exec, wlaunchpad"},
+		{ConfigHyprland, "SUPER+SHIFT+d", "bind = SUPER SHIFT, D, exec, wlaunchpad"},
+		{ConfigHyprland, "F12", "bind = , F12, exec, wlaunchpad"},
+	} {
+		got, err := Binding(tt.kind, tt.key, "wlaunchpad")
+		if err != nil {
+			t.Errorf("%s %s: %s", tt.kind, tt.key, err)
+		} else if got != tt.want {
+			t.Errorf("%s %s: got %q, want %q", tt.kind, tt.key, got, tt.want)
+		}
+	}
+
+	for _, key := range []string{"HYPER+d", "SUPER+", ""} {
+		if _, err := Binding(ConfigSway, key, "wlaunchpad"); err == nil {
+			t.Errorf("expected an error for %q", key)
+		}
+	}
+	if _, err := Binding("river", "SUPER", "wlaunchpad"); err == nil {
+		t.Error("expected an error for an unknown compositor")
+	}
+}
+
+func TestAutostarts(t *testing.T) {
+	if !Autostarts([]byte("set $mod Mod4\nexec wlaunchpad -d -n\n")) {
+		t.Error("expected exec wlaunchpad -d to count")
+	}
+	if !Autostarts([]byte("exec-once = wlaunchpad -c 8 -d\n")) {
+		t.Error("expected Hyprland's exec-once to count")
+	}
+	for _, contents := range []string{"# exec wlaunchpad -d\n", "bindsym Mod4+d exec wlaunchpad\n", "exec wlaunchpad\n"} {
+		if Autostarts([]byte(contents)) {
+			t.Errorf("%q doesn't start the daemon", contents)
+		}
+	}
+}
+
+func TestAddBinding(t *testing.T) {
+	dir := t.TempDir()
+	real := filepath.Join(dir, "dotfiles", "sway")
+	if err := os.MkdirAll(filepath.Dir(real), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(real, []byte("set $mod Mod4"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "config")
+	if err := os.Symlink(real, path); err != nil {
+		t.Fatal(err)
+	}
+
+	line := "bindsym --release Super_L exec wlaunchpad"
+	added, err := AddBinding(path, line)
+	if err != nil || !added {
+		t.Fatalf("expected the binding to be added, got %v, %v", added, err)
+	}
+	contents, _ := ioutil.ReadFile(path)
+	if want := "set $mod Mod4\n\n# toggle wlaunchpad\n" + line + "\n"; string(contents) != want {
+		t.Errorf("got %q, want %q", contents, want)
+	}
+	if info, err := os.Lstat(path); err != nil || info.Mode()&os.ModeSymlink == 0 {
+		t.Error("expected the symlink to be kept")
+	}
+	if info, _ := os.Stat(real); info.Mode().Perm() != 0600 {
+		t.Errorf("expected the permissions to be kept, got %v", info.Mode().Perm())
+	}
+	backup, err := ioutil.ReadFile(real + ".bak")
+	if err != nil || string(backup) != "set $mod Mod4" {
+		t.Errorf("expected a backup of the previous contents, got %q, %v", backup, err)
+	}
+
+	added, err = AddBinding(path, line)
+	if err != nil || added {
+		t.Errorf("expected the binding to be found, got %v, %v", added, err)
+	}
+	if again, _ := ioutil.ReadFile(path); strings.Count(string(again), line) != 1 {
+		t.Errorf("expected the binding once, got %q", again)
+	}
+}
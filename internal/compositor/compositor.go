@@ -0,0 +1,58 @@
+// Package compositor asks Wayland compositors about their outputs, over each
+// one's own IPC.
+package compositor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Output is a monitor in the compositor's layout
+type Output struct {
+	Name string
+	X, Y int
+}
+
+// Backend lists outputs of one kind of compositor
+type Backend interface {
+	Name() string
+	Outputs(ctx context.Context) ([]Output, error)
+}
+
+// Detect returns the backend for the running compositor
+func Detect() Backend {
+	switch {
+	case os.Getenv("SWAYSOCK") != "":
+		return Sway{}
+	case os.Getenv("WAYFIRE_SOCKET") != "":
+		return Wayfire{Socket: os.Getenv("WAYFIRE_SOCKET")}
+	}
+	// river and others without an IPC of their own
+	return Fallback{Wayland{}, WlrRandr{}}
+}
+
+// Fallback tries backends in turn until one lists the outputs
+type Fallback []Backend
+
+func (f Fallback) Name() string {
+	var names []string
+	for _, b := range f {
+		names = append(names, b.Name())
+	}
+	return strings.Join(names, ", ")
+}
+
+func (f Fallback) Outputs(ctx context.Context) ([]Output, error) {
+	var errs []string
+	for _, b := range f {
+		outputs, err := b.Outputs(ctx)
+		if err == nil {
+			return outputs, nil
+		}
+		errs = append(errs, fmt.Sprintf("%s: %s", b.Name(), err))
+	}
+	return nil, errors.New(strings.Join(errs, "; "))
+}
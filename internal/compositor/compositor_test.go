@@ -0,0 +1,79 @@
+package compositor
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestParseWlrRandr(t *testing.T) {
+	out := []byte(`[
+		{"name": "DP-1", "enabled": true, "position": {"x": 0, "y": 0}},
+		{"name": "HDMI-A-1", "enabled": true, "position": {"x": 2560, "y": 0}},
+		{"name": "eDP-1", "enabled": false, "position": {"x": 0, "y": 0}}
+	]`)
+	outputs, err := parseWlrRandr(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(outputs) != 2 || outputs[1] != (Output{Name: "HDMI-A-1", X: 2560}) {
+		t.Errorf("got %+v", outputs)
+	}
+}
+
+func TestParseHyprlandWorkspace(t *testing.T) {
+	for out, want := range map[string]string{
+		`{"id": 2, "name": "2", "monitor": "DP-1", "windows": 3}`:    "2",
+		`{"id": 7, "name": "mail", "monitor": "DP-1", "windows": 1}`: "name:mail",
+		`{"id": -1337, "name": "wlaunchpad", "windows": 0}`:          "name:wlaunchpad",
+	} {
+		got, err := parseHyprlandWorkspace([]byte(out))
+		if err != nil || got != want {
+			t.Errorf("%s: got %q, %v, want %q", out, got, err, want)
+		}
+	}
+}
+
+func TestWayfire(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "wayfire.sock")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		var length uint32
+		binary.Read(conn, binary.LittleEndian, &length)
+		request := make([]byte, length)
+		io.ReadFull(conn, request)
+		var msg struct{ Method string }
+		json.Unmarshal(request, &msg)
+
+		response := []byte(`{"error": "unknown method"}`)
+		if msg.Method == "window-rules/list-outputs" {
+			response = []byte(`[{"id": 1, "name": "DP-2", "geometry": {"x": 1920, "y": 0, "width": 1920, "height": 1080}}]`)
+		}
+		binary.Write(conn, binary.LittleEndian, uint32(len(response)))
+		conn.Write(response)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	outputs, err := Wayfire{Socket: socket}.Outputs(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(outputs) != 1 || outputs[0] != (Output{Name: "DP-2", X: 1920}) {
+		t.Errorf("got %+v", outputs)
+	}
+}
@@ -0,0 +1,86 @@
+package compositor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/ftphikari/wlaunchpad/internal/config"
+)
+
+func init() {
+	config.AddFeature("desktop", "hyprland")
+}
+
+// DesktopWorkspace is the workspace switched to to reveal the desktop, empty
+// unless something gets opened there
+const DesktopWorkspace = "wlaunchpad"
+
+// CanShowDesktop reports whether the running compositor is one ShowDesktop
+// knows how to ask
+func CanShowDesktop() bool {
+	return DetectConfig() != ""
+}
+
+// ShowDesktop hides the windows of the focused output by switching it to
+// DesktopWorkspace. Returns the workspace to go back to with RestoreDesktop,
+// "" when already on DesktopWorkspace.
+func ShowDesktop(ctx context.Context) (string, error) {
+	switch DetectConfig() {
+	case ConfigSway:
+		return swayShowDesktop(ctx)
+	case ConfigHyprland:
+		out, err := exec.CommandContext(ctx, "hyprctl", "-j", "activeworkspace").Output()
+		if err != nil {
+			return "", err
+		}
+		previous, err := parseHyprlandWorkspace(out)
+		if err != nil || previous == "name:"+DesktopWorkspace {
+			return "", err
+		}
+		return previous, hyprlandDispatch(ctx, "workspace", "name:"+DesktopWorkspace)
+	}
+	return "", errors.New("neither sway nor Hyprland is running")
+}
+
+// RestoreDesktop switches back to the workspace ShowDesktop returned
+func RestoreDesktop(ctx context.Context, previous string) error {
+	switch DetectConfig() {
+	case ConfigSway:
+		return swayRestoreDesktop(ctx, previous)
+	case ConfigHyprland:
+		return hyprlandDispatch(ctx, "workspace", previous)
+	}
+	return errors.New("neither sway nor Hyprland is running")
+}
+
+// Returns the active workspace in hyprctl activeworkspace's JSON as dispatch
+// takes it: by ID when numbered, else by name
+func parseHyprlandWorkspace(out []byte) (string, error) {
+	var workspace struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(out, &workspace); err != nil {
+		return "", err
+	}
+	if workspace.ID > 0 && workspace.Name == fmt.Sprint(workspace.ID) {
+		return workspace.Name, nil
+	}
+	return "name:" + workspace.Name, nil
+}
+
+// hyprctl exits fine on failed dispatches, only what it prints tells
+func hyprlandDispatch(ctx context.Context, args ...string) error {
+	out, err := exec.CommandContext(ctx, "hyprctl", append([]string{"dispatch"}, args...)...).Output()
+	if err != nil {
+		return err
+	}
+	if reply := strings.TrimSpace(string(out)); reply != "ok" {
+		return fmt.Errorf("hyprctl dispatch %s: %s", strings.Join(args, " "), reply)
+	}
+	return nil
+}
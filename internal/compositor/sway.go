@@ -0,0 +1,77 @@
+//go:build !nosway
+// +build !nosway
+
+package compositor
+
+import (
+	"context"
+	"strings"
+
+	"github.com/joshuarubin/go-sway"
+
+	"github.com/ftphikari/wlaunchpad/internal/config"
+)
+
+func init() {
+	config.AddFeature("compositor", "sway")
+	config.AddFeature("desktop", "sway")
+}
+
+// Sway lists outputs over sway IPC
+type Sway struct{}
+
+func (Sway) Name() string {
+	return "sway"
+}
+
+func (Sway) Outputs(ctx context.Context) ([]Output, error) {
+	client, err := sway.New(ctx)
+	if err != nil {
+		return nil, err
+	}
+	swayOutputs, err := client.GetOutputs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var outputs []Output
+	for _, o := range swayOutputs {
+		outputs = append(outputs, Output{Name: o.Name, X: int(o.Rect.X), Y: int(o.Rect.Y)})
+	}
+	return outputs, nil
+}
+
+// Switches to DesktopWorkspace, returns the workspace focused before
+func swayShowDesktop(ctx context.Context) (string, error) {
+	client, err := sway.New(ctx)
+	if err != nil {
+		return "", err
+	}
+	workspaces, err := client.GetWorkspaces(ctx)
+	if err != nil {
+		return "", err
+	}
+	var previous string
+	for _, w := range workspaces {
+		if w.Focused {
+			previous = w.Name
+		}
+	}
+	if previous == "" || previous == DesktopWorkspace {
+		return "", nil
+	}
+	_, err = client.RunCommand(ctx, "workspace "+swayQuote(DesktopWorkspace))
+	return previous, err
+}
+
+func swayRestoreDesktop(ctx context.Context, previous string) error {
+	client, err := sway.New(ctx)
+	if err != nil {
+		return err
+	}
+	_, err = client.RunCommand(ctx, "workspace "+swayQuote(previous))
+	return err
+}
+
+func swayQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
@@ -0,0 +1,28 @@
+//go:build nosway
+// +build nosway
+
+package compositor
+
+import (
+	"context"
+	"errors"
+)
+
+// Sway lists outputs with xdg-output, sway IPC being left out of the build
+type Sway struct{}
+
+func (Sway) Name() string {
+	return "sway (xdg-output)"
+}
+
+func (Sway) Outputs(ctx context.Context) ([]Output, error) {
+	return Wayland{}.Outputs(ctx)
+}
+
+func swayShowDesktop(ctx context.Context) (string, error) {
+	return "", errors.New("built without sway support")
+}
+
+func swayRestoreDesktop(ctx context.Context, previous string) error {
+	return errors.New("built without sway support")
+}
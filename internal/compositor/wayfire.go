@@ -0,0 +1,85 @@
+package compositor
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+
+	"github.com/ftphikari/wlaunchpad/internal/config"
+)
+
+func init() {
+	config.AddFeature("compositor", "wayfire")
+}
+
+// Wayfire lists outputs over the IPC of Wayfire's ipc plugin, listening at
+// Socket (WAYFIRE_SOCKET)
+type Wayfire struct {
+	Socket string
+}
+
+func (Wayfire) Name() string {
+	return "wayfire"
+}
+
+func (w Wayfire) Outputs(ctx context.Context) ([]Output, error) {
+	var reply []struct {
+		Name     string `json:"name"`
+		Geometry struct {
+			X int `json:"x"`
+			Y int `json:"y"`
+		} `json:"geometry"`
+	}
+	if err := w.call(ctx, "window-rules/list-outputs", &reply); err != nil {
+		return nil, err
+	}
+	var outputs []Output
+	for _, o := range reply {
+		outputs = append(outputs, Output{Name: o.Name, X: o.Geometry.X, Y: o.Geometry.Y})
+	}
+	return outputs, nil
+}
+
+// Messages both ways are JSON preceded by their length, a little endian uint32
+func (w Wayfire) call(ctx context.Context, method string, reply interface{}) error {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "unix", w.Socket)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	request, err := json.Marshal(map[string]interface{}{"method": method, "data": struct{}{}})
+	if err != nil {
+		return err
+	}
+	if err := binary.Write(conn, binary.LittleEndian, uint32(len(request))); err != nil {
+		return err
+	}
+	if _, err := conn.Write(request); err != nil {
+		return err
+	}
+
+	var length uint32
+	if err := binary.Read(conn, binary.LittleEndian, &length); err != nil {
+		return err
+	}
+	response := make([]byte, length)
+	if _, err := io.ReadFull(conn, response); err != nil {
+		return err
+	}
+	// errors come as {"error": "..."} instead of the reply
+	var failure struct {
+		Error string `json:"error"`
+	}
+	if json.Unmarshal(response, &failure) == nil && failure.Error != "" {
+		return errors.New(failure.Error)
+	}
+	return json.Unmarshal(response, reply)
+}
@@ -0,0 +1,299 @@
+package compositor
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ftphikari/wlaunchpad/internal/config"
+)
+
+func init() {
+	config.AddFeature("compositor", "xdg-output")
+}
+
+// Wayland lists outputs with the xdg-output protocol, spoken to the compositor
+// directly, for compositors without an IPC of their own
+type Wayland struct{}
+
+func (Wayland) Name() string {
+	return "xdg-output"
+}
+
+func (Wayland) Outputs(ctx context.Context) ([]Output, error) {
+	display := os.Getenv("WAYLAND_DISPLAY")
+	if display == "" {
+		display = "wayland-0"
+	}
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "unix", socketPath(display))
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	return xdgOutputs(&wlConn{rw: conn, nextID: 2})
+}
+
+// Returns the socket of a WAYLAND_DISPLAY value, a name in XDG_RUNTIME_DIR or
+// an absolute path
+func socketPath(display string) string {
+	if filepath.IsAbs(display) {
+		return display
+	}
+	return filepath.Join(os.Getenv("XDG_RUNTIME_DIR"), display)
+}
+
+// WaylandSocket returns the path of a Wayland socket accepting connections:
+// the one WAYLAND_DISPLAY names, else the first one in XDG_RUNTIME_DIR.
+// Session variables are often wrong or missing in systemd user services.
+func WaylandSocket() (string, error) {
+	var candidates []string
+	if display := os.Getenv("WAYLAND_DISPLAY"); display != "" {
+		candidates = append(candidates, socketPath(display))
+	}
+	matches, _ := filepath.Glob(filepath.Join(os.Getenv("XDG_RUNTIME_DIR"), "wayland-*"))
+	for _, path := range matches {
+		if !strings.HasSuffix(path, ".lock") {
+			candidates = append(candidates, path)
+		}
+	}
+	for _, path := range candidates {
+		conn, err := net.DialTimeout("unix", path, time.Second)
+		if err == nil {
+			conn.Close()
+			return path, nil
+		}
+	}
+	return "", errors.New("no Wayland socket accepts connections")
+}
+
+// Object ID of wl_display, the only one existing from the start
+const wlDisplay = 1
+
+// Opcodes of the requests and events we use
+const (
+	displaySync        = 0
+	displayGetRegistry = 1
+	displayError       = 0
+	registryBind       = 0
+	registryGlobal     = 0
+	callbackDone       = 0
+
+	xdgOutputManagerGetXdgOutput = 1
+	xdgOutputLogicalPosition     = 0
+	xdgOutputName                = 3
+)
+
+// A Wayland connection, enough to ask questions and read the answers
+type wlConn struct {
+	rw     io.ReadWriter
+	nextID uint32
+}
+
+type wlMessage struct {
+	object uint32
+	opcode uint16
+	args   []byte
+}
+
+func (c *wlConn) newID() uint32 {
+	id := c.nextID
+	c.nextID++
+	return id
+}
+
+// Sends a request, args are uint32s, int32s and strings
+func (c *wlConn) send(object uint32, opcode uint16, args ...interface{}) error {
+	var body []byte
+	for _, arg := range args {
+		switch v := arg.(type) {
+		case uint32:
+			body = appendUint32(body, v)
+		case string:
+			// length including the terminating NUL, padded to 32 bits
+			body = appendUint32(body, uint32(len(v)+1))
+			body = append(body, v...)
+			body = append(body, make([]byte, 4-len(v)%4)...)
+		}
+	}
+	header := make([]byte, 8)
+	binary.LittleEndian.PutUint32(header, object)
+	binary.LittleEndian.PutUint32(header[4:], uint32(8+len(body))<<16|uint32(opcode))
+	_, err := c.rw.Write(append(header, body...))
+	return err
+}
+
+func appendUint32(b []byte, v uint32) []byte {
+	var buf [4]byte
+	binary.LittleEndian.PutUint32(buf[:], v)
+	return append(b, buf[:]...)
+}
+
+func (c *wlConn) read() (wlMessage, error) {
+	header := make([]byte, 8)
+	if _, err := io.ReadFull(c.rw, header); err != nil {
+		return wlMessage{}, err
+	}
+	sizeOpcode := binary.LittleEndian.Uint32(header[4:])
+	size := sizeOpcode >> 16
+	if size < 8 {
+		return wlMessage{}, errors.New("invalid Wayland message")
+	}
+	m := wlMessage{
+		object: binary.LittleEndian.Uint32(header),
+		opcode: uint16(sizeOpcode),
+		args:   make([]byte, size-8),
+	}
+	_, err := io.ReadFull(c.rw, m.args)
+	return m, err
+}
+
+// Reads messages, passing them to handle, until the compositor has handled
+// everything sent so far
+func (c *wlConn) roundtrip(handle func(wlMessage)) error {
+	callback := c.newID()
+	if err := c.send(wlDisplay, displaySync, callback); err != nil {
+		return err
+	}
+	for {
+		m, err := c.read()
+		if err != nil {
+			return err
+		}
+		switch {
+		case m.object == callback && m.opcode == callbackDone:
+			return nil
+		case m.object == wlDisplay && m.opcode == displayError:
+			// object, code, message
+			msg, _ := (&argReader{b: m.args[min(8, len(m.args)):]}).string()
+			return fmt.Errorf("Wayland error: %s", msg)
+		}
+		handle(m)
+	}
+}
+
+// Reads the arguments of an event
+type argReader struct {
+	b []byte
+}
+
+func (r *argReader) uint32() (uint32, error) {
+	if len(r.b) < 4 {
+		return 0, errors.New("short Wayland message")
+	}
+	v := binary.LittleEndian.Uint32(r.b)
+	r.b = r.b[4:]
+	return v, nil
+}
+
+func (r *argReader) string() (string, error) {
+	n, err := r.uint32()
+	if err != nil {
+		return "", err
+	}
+	padded := (int(n) + 3) &^ 3
+	if n == 0 || len(r.b) < padded {
+		return "", errors.New("short Wayland message")
+	}
+	s := string(r.b[:n-1])
+	r.b = r.b[padded:]
+	return s, nil
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func xdgOutputs(c *wlConn) ([]Output, error) {
+	type global struct {
+		name    uint32
+		version uint32
+	}
+	registry := c.newID()
+	if err := c.send(wlDisplay, displayGetRegistry, registry); err != nil {
+		return nil, err
+	}
+	var wlOutputs []global
+	var manager *global
+	err := c.roundtrip(func(m wlMessage) {
+		if m.object != registry || m.opcode != registryGlobal {
+			return
+		}
+		r := &argReader{b: m.args}
+		name, _ := r.uint32()
+		iface, _ := r.string()
+		version, _ := r.uint32()
+		switch iface {
+		case "wl_output":
+			wlOutputs = append(wlOutputs, global{name, version})
+		case "zxdg_output_manager_v1":
+			manager = &global{name, version}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	if manager == nil {
+		return nil, errors.New("the compositor doesn't support xdg-output")
+	}
+
+	// names came with version 2
+	managerID := c.newID()
+	if err := c.send(registry, registryBind, manager.name, "zxdg_output_manager_v1", uint32(min(int(manager.version), 3)), managerID); err != nil {
+		return nil, err
+	}
+	outputs := make(map[uint32]*Output)
+	var order []uint32
+	for _, o := range wlOutputs {
+		outputID := c.newID()
+		if err := c.send(registry, registryBind, o.name, "wl_output", uint32(1), outputID); err != nil {
+			return nil, err
+		}
+		xdgOutputID := c.newID()
+		if err := c.send(managerID, xdgOutputManagerGetXdgOutput, xdgOutputID, outputID); err != nil {
+			return nil, err
+		}
+		outputs[xdgOutputID] = &Output{}
+		order = append(order, xdgOutputID)
+	}
+
+	err = c.roundtrip(func(m wlMessage) {
+		output, ok := outputs[m.object]
+		if !ok {
+			return
+		}
+		r := &argReader{b: m.args}
+		switch m.opcode {
+		case xdgOutputLogicalPosition:
+			x, _ := r.uint32()
+			y, _ := r.uint32()
+			output.X, output.Y = int(int32(x)), int(int32(y))
+		case xdgOutputName:
+			output.Name, _ = r.string()
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var result []Output
+	for _, id := range order {
+		if outputs[id].Name != "" {
+			result = append(result, *outputs[id])
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,125 @@
+package compositor
+
+import (
+	"net"
+	"path/filepath"
+	"testing"
+)
+
+// Plays a compositor with two outputs for xdgOutputs
+func fakeCompositor(t *testing.T, conn net.Conn) {
+	c := &wlConn{rw: conn}
+	var registry, manager uint32
+	xdgOutputs := make(map[uint32]uint32) // wl_output ID -> xdg output ID
+	bound := make(map[uint32]uint32)      // wl_output ID -> global name
+	for {
+		m, err := c.read()
+		if err != nil {
+			return
+		}
+		r := &argReader{b: m.args}
+		switch {
+		case m.object == wlDisplay && m.opcode == displayGetRegistry:
+			registry, _ = r.uint32()
+			c.send(registry, registryGlobal, uint32(10), "wl_output", uint32(4))
+			c.send(registry, registryGlobal, uint32(11), "wl_compositor", uint32(5))
+			c.send(registry, registryGlobal, uint32(12), "wl_output", uint32(4))
+			c.send(registry, registryGlobal, uint32(13), "zxdg_output_manager_v1", uint32(3))
+		case m.object == wlDisplay && m.opcode == displaySync:
+			callback, _ := r.uint32()
+			for outputID, xdgOutputID := range xdgOutputs {
+				name, x := "DP-1", uint32(0)
+				if bound[outputID] == 12 {
+					name, x = "HDMI-A-1", uint32(1920)
+				}
+				c.send(xdgOutputID, xdgOutputLogicalPosition, x, uint32(0))
+				c.send(xdgOutputID, xdgOutputName, name)
+			}
+			xdgOutputs = make(map[uint32]uint32)
+			c.send(callback, callbackDone, uint32(0))
+		case m.object == registry && m.opcode == registryBind:
+			name, _ := r.uint32()
+			iface, _ := r.string()
+			r.uint32()
+			id, _ := r.uint32()
+			if iface == "zxdg_output_manager_v1" {
+				manager = id
+			} else {
+				bound[id] = name
+			}
+		case m.object == manager && m.opcode == xdgOutputManagerGetXdgOutput:
+			id, _ := r.uint32()
+			output, _ := r.uint32()
+			xdgOutputs[output] = id
+		default:
+			t.Errorf("unexpected request %d on %d", m.opcode, m.object)
+		}
+	}
+}
+
+func TestXdgOutputs(t *testing.T) {
+	// buffered, unlike net.Pipe: both sides write before reading
+	socket := filepath.Join(t.TempDir(), "wayland-0")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	go func() {
+		server, err := listener.Accept()
+		if err == nil {
+			fakeCompositor(t, server)
+			server.Close()
+		}
+	}()
+	client, err := net.Dial("unix", socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	outputs, err := xdgOutputs(&wlConn{rw: client, nextID: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Output{{Name: "DP-1"}, {Name: "HDMI-A-1", X: 1920}}
+	if len(outputs) != len(want) {
+		t.Fatalf("got %+v", outputs)
+	}
+	for i := range want {
+		if outputs[i] != want[i] {
+			t.Errorf("output %d: got %+v, expected %+v", i, outputs[i], want[i])
+		}
+	}
+}
+
+func TestWaylandSocket(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_RUNTIME_DIR", dir)
+	t.Setenv("WAYLAND_DISPLAY", "wayland-0")
+
+	if _, err := WaylandSocket(); err == nil {
+		t.Error("expected no socket to be found")
+	}
+
+	// a stale socket nobody listens on any more, and the live one
+	stale, err := net.Listen("unix", filepath.Join(dir, "wayland-0"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	stale.Close()
+	live, err := net.Listen("unix", filepath.Join(dir, "wayland-1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer live.Close()
+
+	path, err := WaylandSocket()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(dir, "wayland-1"); path != want {
+		t.Errorf("got %s, want %s", path, want)
+	}
+}
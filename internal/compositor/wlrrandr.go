@@ -0,0 +1,50 @@
+package compositor
+
+import (
+	"context"
+	"encoding/json"
+	"os/exec"
+
+	"github.com/ftphikari/wlaunchpad/internal/config"
+)
+
+func init() {
+	config.AddFeature("compositor", "wlr-randr")
+}
+
+// WlrRandr lists outputs with wlr-randr, over the wlr-output-management
+// protocol implemented by river and other wlroots compositors
+type WlrRandr struct{}
+
+func (WlrRandr) Name() string {
+	return "wlr-randr"
+}
+
+func (WlrRandr) Outputs(ctx context.Context) ([]Output, error) {
+	out, err := exec.CommandContext(ctx, "wlr-randr", "--json").Output()
+	if err != nil {
+		return nil, err
+	}
+	return parseWlrRandr(out)
+}
+
+func parseWlrRandr(out []byte) ([]Output, error) {
+	var reply []struct {
+		Name     string `json:"name"`
+		Enabled  bool   `json:"enabled"`
+		Position struct {
+			X int `json:"x"`
+			Y int `json:"y"`
+		} `json:"position"`
+	}
+	if err := json.Unmarshal(out, &reply); err != nil {
+		return nil, err
+	}
+	var outputs []Output
+	for _, o := range reply {
+		if o.Enabled {
+			outputs = append(outputs, Output{Name: o.Name, X: o.Position.X, Y: o.Position.Y})
+		}
+	}
+	return outputs, nil
+}
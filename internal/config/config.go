@@ -0,0 +1,228 @@
+// Package config reads the wlaunchpad config file.
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Config holds the config file contents: section name -> key -> raw value.
+// Only the subset of TOML we need is supported: [section] headers, key = value
+// pairs, strings, numbers, booleans and arrays of strings.
+type Config map[string]map[string]string
+
+// Dir returns the directory of the config file
+func Dir() string {
+	if os.Getenv("XDG_CONFIG_HOME") != "" {
+		return filepath.Join(os.Getenv("XDG_CONFIG_HOME"), "wlaunchpad")
+	}
+	return filepath.Join(os.Getenv("HOME"), ".config", "wlaunchpad")
+}
+
+// StateDir returns the directory for state kept between runs
+func StateDir() string {
+	if os.Getenv("XDG_STATE_HOME") != "" {
+		return filepath.Join(os.Getenv("XDG_STATE_HOME"), "wlaunchpad")
+	}
+	return filepath.Join(os.Getenv("HOME"), ".local", "state", "wlaunchpad")
+}
+
+// CacheDir returns the directory for files that may be deleted anytime, like
+// logs
+func CacheDir() string {
+	if os.Getenv("XDG_CACHE_HOME") != "" {
+		return filepath.Join(os.Getenv("XDG_CACHE_HOME"), "wlaunchpad")
+	}
+	return filepath.Join(os.Getenv("HOME"), ".cache", "wlaunchpad")
+}
+
+// DataDir returns the directory for data the user made, like pinned entries
+func DataDir() string {
+	if os.Getenv("XDG_DATA_HOME") != "" {
+		return filepath.Join(os.Getenv("XDG_DATA_HOME"), "wlaunchpad")
+	}
+	return filepath.Join(os.Getenv("HOME"), ".local", "share", "wlaunchpad")
+}
+
+// Format changes of the config file, see Migrations
+var migrations = Migrations{
+	// 0 -> 1: unversioned files need no changes. Kept for the files given
+	// "version = 1" by earlier releases.
+	func(contents string) string { return contents },
+}
+
+// Load reads the config file at path, migrating it to the current version
+// first
+func Load(path string) (Config, error) {
+	return migrations.Load(path)
+}
+
+// Load reads a file in the config file format at path, migrating it to the
+// current version first
+func (m Migrations) Load(path string) (Config, error) {
+	if err := m.Migrate(path); err != nil {
+		log.Printf("Couldn't migrate %s: %s", path, err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return make(Config), err
+	}
+	defer f.Close()
+
+	return Parse(f)
+}
+
+// Parse reads config file contents
+func Parse(in io.Reader) (Config, error) {
+	c := make(Config)
+	section := ""
+	c[section] = make(map[string]string)
+
+	scanner := bufio.NewScanner(in)
+	for n := 1; scanner.Scan(); n++ {
+		l := strings.TrimSpace(stripComment(scanner.Text()))
+		if l == "" {
+			continue
+		}
+
+		if strings.HasPrefix(l, "[") && strings.HasSuffix(l, "]") {
+			section = strings.TrimSpace(l[1 : len(l)-1])
+			if _, ok := c[section]; !ok {
+				c[section] = make(map[string]string)
+			}
+			continue
+		}
+
+		key, value := keypair(l)
+		if key == l {
+			return c, fmt.Errorf("line %d: expected key = value", n)
+		}
+		c[section][unquote(key)] = value
+	}
+	return c, scanner.Err()
+}
+
+// Removes a trailing # comment, unless the # is inside a quoted string
+func stripComment(l string) string {
+	var quote rune
+	for i, r := range l {
+		switch {
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote == 0 && (r == '"' || r == '\''):
+			quote = r
+		case quote == 0 && r == '#':
+			return l[:i]
+		}
+	}
+	return l
+}
+
+// Subsections returns names of the sections starting with "prefix.", without
+// the prefix
+func (c Config) Subsections(prefix string) []string {
+	var names []string
+	for s := range c {
+		if strings.HasPrefix(s, prefix+".") {
+			names = append(names, strings.TrimPrefix(s, prefix+"."))
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Keys returns the keys set in the section, sorted
+func (c Config) Keys(section string) []string {
+	var keys []string
+	for key := range c[section] {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Has reports whether the key is set in the section
+func (c Config) Has(section, key string) bool {
+	_, ok := c[section][key]
+	return ok
+}
+
+// Str returns a string value, or fallback if not set
+func (c Config) Str(section, key, fallback string) string {
+	value, ok := c[section][key]
+	if !ok {
+		return fallback
+	}
+	return unquote(value)
+}
+
+// Int returns an integer value, or fallback if not set or invalid
+func (c Config) Int(section, key string, fallback int) int {
+	i, err := strconv.Atoi(c[section][key])
+	if err != nil {
+		return fallback
+	}
+	return i
+}
+
+// Bool returns a boolean value, or fallback if not set or invalid
+func (c Config) Bool(section, key string, fallback bool) bool {
+	b, err := strconv.ParseBool(c[section][key])
+	if err != nil {
+		return fallback
+	}
+	return b
+}
+
+// List returns an array of strings, or nil if not set or not an array
+func (c Config) List(section, key string) []string {
+	value := strings.TrimSpace(c[section][key])
+	if !strings.HasPrefix(value, "[") || !strings.HasSuffix(value, "]") {
+		return nil
+	}
+
+	var items []string
+	var quote rune
+	start := 1
+	for i, r := range value {
+		switch {
+		case i == 0:
+			continue
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote == 0 && (r == '"' || r == '\''):
+			quote = r
+		case quote == 0 && (r == ',' || i == len(value)-1):
+			if item := strings.TrimSpace(value[start:i]); item != "" {
+				items = append(items, unquote(item))
+			}
+			start = i + 1
+		}
+	}
+	return items
+}
+
+func unquote(s string) string {
+	if len(s) >= 2 && s[0] == '\'' && s[len(s)-1] == '\'' {
+		return s[1 : len(s)-1]
+	}
+	if u, err := strconv.Unquote(s); err == nil {
+		return u
+	}
+	return s
+}
+
+func keypair(s string) (string, string) {
+	if idx := strings.IndexRune(s, '='); idx > 0 {
+		return strings.TrimSpace(s[:idx]), strings.TrimSpace(s[idx+1:])
+	}
+	return s, ""
+}
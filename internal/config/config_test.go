@@ -0,0 +1,56 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseConfig(t *testing.T) {
+	const contents = `# top level comment
+columns = 8
+
+[set.work]
+name = "Work # stuff"
+apps = ["slack.desktop", 'firefox.desktop', "code"] # trailing comment
+delay = 1500
+
+[aliases]
+word = "onlyoffice"
+"gimp" = "org.gimp.GIMP"
+`
+
+	c, err := Parse(strings.NewReader(contents))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if c.Int("", "columns", 0) != 8 {
+		t.Error("failed to parse top level integer")
+	}
+
+	if c.Str("set.work", "name", "") != "Work # stuff" {
+		t.Errorf("failed to parse quoted string, got %q", c.Str("set.work", "name", ""))
+	}
+
+	apps := c.List("set.work", "apps")
+	if len(apps) != 3 || apps[0] != "slack.desktop" || apps[1] != "firefox.desktop" || apps[2] != "code" {
+		t.Errorf("failed to parse list, got %q", apps)
+	}
+
+	if keys := c.Keys("aliases"); len(keys) != 2 || keys[0] != "gimp" || keys[1] != "word" {
+		t.Errorf("failed to list keys, got %q", keys)
+	}
+	if c.Str("aliases", "gimp", "") != "org.gimp.GIMP" {
+		t.Error("failed to parse quoted key")
+	}
+
+	if sets := c.Subsections("set"); len(sets) != 1 || sets[0] != "work" {
+		t.Errorf("failed to list subsections, got %q", sets)
+	}
+}
+
+func TestParseConfigError(t *testing.T) {
+	if _, err := Parse(strings.NewReader("[set.work]\nnonsense\n")); err == nil {
+		t.Error("expected an error for a line without value")
+	}
+}
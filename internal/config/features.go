@@ -0,0 +1,29 @@
+package config
+
+import "sort"
+
+// Capabilities compiled in: kind -> names
+var features = make(map[string][]string)
+
+// AddFeature records a capability of a kind, like "compositor" or "ipc", as
+// compiled in. Files left out by build tags call it from init.
+func AddFeature(kind, name string) {
+	features[kind] = append(features[kind], name)
+}
+
+// FeatureKinds returns the kinds of capabilities compiled in, sorted
+func FeatureKinds() []string {
+	var kinds []string
+	for kind := range features {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+// Features returns the capabilities of a kind compiled in, sorted
+func Features(kind string) []string {
+	names := append([]string(nil), features[kind]...)
+	sort.Strings(names)
+	return names
+}
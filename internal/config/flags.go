@@ -0,0 +1,105 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Top level keys of the config file for flags whose names are too short to
+// tell what they are; the others go by their own
+var flagKeys = map[string]string{
+	"c": "columns",
+	"i": "icon-size",
+	"s": "spacing",
+	"t": "term",
+	"o": "output",
+	"d": "daemon",
+	"n": "no-show",
+}
+
+// Flags which only make sense on the command line
+var commandLineOnly = map[string]bool{"config": true, "version": true, "launch": true, "url": true}
+
+// ApplyFlags sets the flags of fs not set on the command line, listed in set,
+// from the top level keys of the config file:
+//
+//	columns = 8
+//	icon-size = 96
+//	term = "kitty"
+//	style = "~/.config/wlaunchpad/style.css"
+//
+// Flags given on the command line win. Returns the names of the flags set,
+// and what's wrong with the keys which couldn't be applied.
+func (c Config) ApplyFlags(fs *flag.FlagSet, set map[string]bool) (map[string]bool, []error) {
+	keys := make(map[string]string)
+	fs.VisitAll(func(f *flag.Flag) {
+		if commandLineOnly[f.Name] {
+			return
+		}
+		if key, ok := flagKeys[f.Name]; ok {
+			keys[key] = f.Name
+		} else {
+			keys[f.Name] = f.Name
+		}
+	})
+
+	applied := make(map[string]bool)
+	var errs []error
+	for _, key := range c.Keys("") {
+		name, ok := keys[key]
+		if !ok {
+			// the version of the file format
+			if key != "version" {
+				errs = append(errs, fmt.Errorf("unknown setting %q", key))
+			}
+			continue
+		}
+		if set[name] {
+			continue
+		}
+		// arrays set repeatable flags once per value
+		values := c.List("", key)
+		if values == nil {
+			values = []string{c.Str("", key, "")}
+		}
+		ok = true
+		for _, value := range values {
+			if strings.HasPrefix(value, "~/") {
+				value = filepath.Join(os.Getenv("HOME"), value[2:])
+			}
+			if err := fs.Set(name, value); err != nil {
+				errs = append(errs, fmt.Errorf("%s: invalid value %q: %s", key, value, err))
+				ok = false
+				break
+			}
+		}
+		if ok {
+			applied[name] = true
+		}
+	}
+	return applied, errs
+}
+
+// Paths is the value of a flag which may be given more than once, each time
+// with a path. An empty value clears the ones given before, resetting the
+// flag to its default.
+type Paths []string
+
+func (p *Paths) String() string {
+	if p == nil {
+		return ""
+	}
+	return strings.Join(*p, string(filepath.ListSeparator))
+}
+
+func (p *Paths) Set(value string) error {
+	if value == "" {
+		*p = nil
+		return nil
+	}
+	*p = append(*p, value)
+	return nil
+}
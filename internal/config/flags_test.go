@@ -0,0 +1,90 @@
+package config
+
+import (
+	"flag"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestApplyFlags(t *testing.T) {
+	fs := flag.NewFlagSet("wlaunchpad", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	columns := fs.Uint("c", 6, "")
+	iconSize := fs.Int("i", 64, "")
+	fs.Uint("s", 20, "")
+	term := fs.String("t", "foot", "")
+	style := fs.String("style", "", "")
+	badges := fs.Bool("badges", false, "")
+	fs.String("config", "", "")
+	if err := fs.Parse([]string{"-i", "48"}); err != nil {
+		t.Fatal(err)
+	}
+	set := map[string]bool{"i": true}
+
+	c, err := Parse(strings.NewReader(`version = 1
+columns = 8
+icon-size = 96
+term = "kitty"
+style = "~/style.css"
+badges = true
+spacing = "wide"
+colums = 4
+config = "/etc/other.toml"
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("HOME", "/home/me")
+
+	applied, errs := c.ApplyFlags(fs, set)
+	if *columns != 8 || *term != "kitty" || !*badges {
+		t.Errorf("failed to apply the config, got %d, %q, %v", *columns, *term, *badges)
+	}
+	if *iconSize != 48 {
+		t.Errorf("the command line should win, got %d", *iconSize)
+	}
+	if *style != "/home/me/style.css" {
+		t.Errorf("failed to expand ~, got %q", *style)
+	}
+	if !applied["c"] || applied["i"] {
+		t.Errorf("got %v", applied)
+	}
+
+	var got []string
+	for _, err := range errs {
+		got = append(got, err.Error())
+	}
+	want := []string{`unknown setting "colums"`, `unknown setting "config"`, `spacing: invalid value "wide": parse error`}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got errors %q, want %q", got, want)
+	}
+}
+
+func TestApplyRepeatableFlags(t *testing.T) {
+	fs := flag.NewFlagSet("wlaunchpad", flag.ContinueOnError)
+	var dirs Paths
+	fs.Var(&dirs, "app-dir", "")
+	c, err := Parse(strings.NewReader(`app-dir = ["~/launchers", "/opt/apps"]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("HOME", "/home/me")
+
+	if _, errs := c.ApplyFlags(fs, nil); len(errs) != 0 {
+		t.Fatal(errs)
+	}
+	if dirs.String() != "/home/me/launchers:/opt/apps" {
+		t.Errorf("got %q", dirs)
+	}
+	// reset before reloading the config
+	fs.Set("app-dir", "")
+	if len(dirs) != 0 {
+		t.Errorf("not cleared: %q", dirs)
+	}
+
+	fs.Parse([]string{"-app-dir", "/a", "-app-dir", "/b"})
+	if _, errs := c.ApplyFlags(fs, map[string]bool{"app-dir": true}); len(errs) != 0 || dirs.String() != "/a:/b" {
+		t.Errorf("the command line should win, got %q, %v", dirs, errs)
+	}
+}
@@ -0,0 +1,121 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Migrations upgrade the contents of a versioned file: Migrations[n] turns
+// version n into version n+1. The version is kept in a top level
+// "version = n" line, files without one are version 0.
+type Migrations []func(contents string) string
+
+// Version returns the version files are migrated to
+func (m Migrations) Version() int {
+	return len(m)
+}
+
+// Migrate upgrades the file at path to the current version. The previous
+// contents are kept next to it in path.v<n>.bak and the new ones replace the
+// file atomically. Missing files, and files no migration changes, are left
+// alone.
+func (m Migrations) Migrate(path string) error {
+	contents, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	version := FileVersion(string(contents))
+	switch {
+	case version == m.Version():
+		return nil
+	case version > m.Version():
+		return fmt.Errorf("version %d is newer than the supported %d", version, m.Version())
+	}
+
+	migrated := string(contents)
+	for _, migrate := range m[version:] {
+		migrated = migrate(migrated)
+	}
+	if migrated == string(contents) {
+		// hand-edited files stay as they are, running the migrations again
+		// next time costs nothing
+		return nil
+	}
+	migrated = setVersion(migrated, m.Version())
+
+	backup := fmt.Sprintf("%s.v%d.bak", path, version)
+	if err := ioutil.WriteFile(backup, contents, info.Mode().Perm()); err != nil {
+		return err
+	}
+	if err := WriteFile(path, []byte(migrated), info.Mode().Perm()); err != nil {
+		return err
+	}
+	log.Printf("Migrated %s from version %d to %d, previous contents kept in %s\n", path, version, m.Version(), backup)
+	return nil
+}
+
+// FileVersion returns the version set by the top level "version = n" line
+func FileVersion(contents string) int {
+	scanner := bufio.NewScanner(strings.NewReader(contents))
+	for scanner.Scan() {
+		l := strings.TrimSpace(stripComment(scanner.Text()))
+		if strings.HasPrefix(l, "[") {
+			break
+		}
+		if key, value := keypair(l); key == "version" {
+			version, _ := strconv.Atoi(value)
+			return version
+		}
+	}
+	return 0
+}
+
+// Replaces the top level version line, or adds one at the top
+func setVersion(contents string, version int) string {
+	lines := strings.SplitAfter(contents, "\n")
+	for i, l := range lines {
+		l = strings.TrimSpace(stripComment(l))
+		if strings.HasPrefix(l, "[") {
+			break
+		}
+		if key, _ := keypair(l); key == "version" {
+			lines[i] = fmt.Sprintf("version = %d\n", version)
+			return strings.Join(lines, "")
+		}
+	}
+	return fmt.Sprintf("version = %d\n", version) + contents
+}
+
+// WriteFile replaces the file at path atomically, creating its directory if
+// needed. When path is a symlink the file it points to is replaced, as for
+// config files kept with dotfiles.
+func WriteFile(path string, data []byte, perm os.FileMode) error {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, perm); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
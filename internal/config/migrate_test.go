@@ -0,0 +1,74 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMigrate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	const old = "# my config\n[set.work]\napps = ['a']\n"
+	if err := ioutil.WriteFile(path, []byte(old), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	migrations := Migrations{
+		func(contents string) string { return contents },
+		func(contents string) string { return strings.ReplaceAll(contents, "[set.", "[sets.") },
+	}
+	if err := migrations.Migrate(path); err != nil {
+		t.Fatal(err)
+	}
+
+	contents, _ := ioutil.ReadFile(path)
+	if string(contents) != "version = 2\n# my config\n[sets.work]\napps = ['a']\n" {
+		t.Errorf("unexpected contents after migration:\n%s", contents)
+	}
+	if backup, _ := ioutil.ReadFile(path + ".v0.bak"); string(backup) != old {
+		t.Errorf("unexpected backup:\n%s", backup)
+	}
+
+	if err := (Migrations{nil}).Migrate(path); err == nil {
+		t.Error("expected an error for a file newer than supported")
+	}
+}
+
+func TestMigrateUnchanged(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	const contents = "# my config\ncolumns = 6\n"
+	ioutil.WriteFile(path, []byte(contents), 0600)
+
+	if err := (Migrations{func(contents string) string { return contents }}).Migrate(path); err != nil {
+		t.Fatal(err)
+	}
+	if after, _ := ioutil.ReadFile(path); string(after) != contents {
+		t.Errorf("expected the file untouched, got:\n%s", after)
+	}
+	if _, err := os.Stat(path + ".v0.bak"); !os.IsNotExist(err) {
+		t.Errorf("expected no backup, got %v", err)
+	}
+}
+
+func TestMigrateSymlink(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "dotfiles.toml")
+	ioutil.WriteFile(target, []byte("[set.work]\n"), 0600)
+	path := filepath.Join(dir, "config.toml")
+	if err := os.Symlink(target, path); err != nil {
+		t.Fatal(err)
+	}
+
+	migrations := Migrations{func(contents string) string { return strings.ReplaceAll(contents, "[set.", "[sets.") }}
+	if err := migrations.Migrate(path); err != nil {
+		t.Fatal(err)
+	}
+	if info, err := os.Lstat(path); err != nil || info.Mode()&os.ModeSymlink == 0 {
+		t.Errorf("expected %s to stay a symlink, %v", path, err)
+	}
+	if contents, _ := ioutil.ReadFile(target); string(contents) != "version = 1\n[sets.work]\n" {
+		t.Errorf("unexpected contents of the target:\n%s", contents)
+	}
+}
@@ -0,0 +1,43 @@
+package config
+
+import "time"
+
+// Build metadata, set with
+// -ldflags "-X github.com/ftphikari/wlaunchpad/internal/config.Version=v0.4.0"
+// and likewise for the others, see build.sh
+var (
+	Version   = "dev"
+	Commit    = "unknown"
+	BuildDate = "unknown"
+)
+
+// Settings holds the command line options
+type Settings struct {
+	Debug         bool
+	Daemon        bool
+	NoShow        bool
+	StyleFile     string
+	TargetOutput  string
+	IconSize      int
+	Columns       uint
+	Spacing       uint
+	ConfigFile    string
+	Term          string
+	Badges        bool
+	LauncherEntry bool
+	DryRun        bool
+	URL           string
+	Gamepad       bool
+	TV            bool
+	OSK           bool
+	Launch        string
+	Category      string
+	AllDesktops   bool
+	NoTryExec     bool
+	// "wayland", "x11" or "auto"
+	Backend string
+	// how long the window stays hidden before it's destroyed, 0 for ever
+	Hibernate time.Duration
+	// looked for desktop files in after the XDG ones
+	AppDirs Paths
+}
@@ -0,0 +1,23 @@
+package entries
+
+// MainCategories are the main categories of the desktop menu spec, which every
+// application should list one of
+var MainCategories = []string{
+	"AudioVideo", "Development", "Education", "Game", "Graphics", "Network",
+	"Office", "Science", "Settings", "System", "Utility",
+}
+
+// Categories returns the main categories the visible entries of the list are
+// in, in the order of MainCategories
+func Categories(list []DesktopEntry) []string {
+	var found []string
+	for _, category := range MainCategories {
+		for _, entry := range list {
+			if !entry.NoDisplay && entry.InCategory(category) {
+				found = append(found, category)
+				break
+			}
+		}
+	}
+	return found
+}
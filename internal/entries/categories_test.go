@@ -0,0 +1,20 @@
+package entries
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCategories(t *testing.T) {
+	list := []DesktopEntry{
+		{Category: "Network;WebBrowser;"},
+		{Category: "Game;ArcadeGame;"},
+		{Category: "Office;", NoDisplay: true},
+		{Category: "AudioVideo;Audio;"},
+		{Category: "game"},
+	}
+	want := []string{"AudioVideo", "Game", "Network"}
+	if got := Categories(list); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, expected %q", got, want)
+	}
+}
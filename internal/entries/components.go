@@ -0,0 +1,193 @@
+package entries
+
+import (
+	"encoding/xml"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// StateID returns the ID state kept about the entry is saved under: its
+// component ID if it has one, else its desktop ID
+func (e DesktopEntry) StateID() string {
+	if e.ComponentID != "" {
+		return e.ComponentID
+	}
+	return e.DesktopID
+}
+
+// MetainfoDirs returns the directories of AppStream metainfo files, next to
+// AppDirs
+func MetainfoDirs() []string {
+	var dirs []string
+	for _, dir := range AppDirs() {
+		share := filepath.Dir(dir)
+		// appdata is the name used before AppStream 0.9
+		dirs = append(dirs, filepath.Join(share, "metainfo"), filepath.Join(share, "appdata"))
+	}
+	return dirs
+}
+
+// What a metainfo file says, and when it was read
+type component struct {
+	modTime   time.Time
+	id        string
+	desktopID string
+}
+
+// What a metainfo directory held when last read
+type metainfoDir struct {
+	modTime time.Time
+	paths   []string
+}
+
+// Metainfo files as last read: path -> component, and the directories they
+// are in. Directories are read again only once files were added, removed or
+// replaced in them, as package managers do, and then only the files changed
+// since are parsed again.
+var (
+	componentsMu sync.Mutex
+	components   = make(map[string]component)
+	metainfoDirs = make(map[string]metainfoDir)
+)
+
+// ComponentIDs returns the AppStream component IDs of the apps described by
+// the metainfo files in dirs: desktop ID -> component ID
+func ComponentIDs(dirs []string) map[string]string {
+	componentsMu.Lock()
+	defer componentsMu.Unlock()
+
+	present := make(map[string]bool)
+	ids := make(map[string]string)
+	for _, dir := range dirs {
+		info, err := os.Stat(dir)
+		if err != nil {
+			delete(metainfoDirs, dir)
+			continue
+		}
+		d, ok := metainfoDirs[dir]
+		if !ok || !d.modTime.Equal(info.ModTime()) {
+			d = readMetainfoDir(dir)
+			d.modTime = info.ModTime()
+			metainfoDirs[dir] = d
+		}
+		for _, path := range d.paths {
+			present[path] = true
+			c := components[path]
+			if c.id == "" || c.desktopID == "" {
+				continue
+			}
+			if _, ok := ids[c.desktopID]; !ok {
+				ids[c.desktopID] = c.id
+			}
+		}
+	}
+	for path := range components {
+		if !present[path] {
+			delete(components, path)
+		}
+	}
+	return ids
+}
+
+// Lists the metainfo files in dir, parsing the ones changed since last read
+func readMetainfoDir(dir string) metainfoDir {
+	var d metainfoDir
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return d
+	}
+	for _, file := range files {
+		if !strings.HasSuffix(file.Name(), ".xml") {
+			continue
+		}
+		path := filepath.Join(dir, file.Name())
+		d.paths = append(d.paths, path)
+		c, ok := components[path]
+		if !ok || !c.modTime.Equal(file.ModTime()) {
+			c = readComponent(path)
+			c.modTime = file.ModTime()
+			components[path] = c
+		}
+	}
+	return d
+}
+
+// Reads the component ID and desktop ID of a metainfo file, leaving them empty
+// when it has none
+func readComponent(path string) component {
+	f, err := os.Open(path)
+	if err != nil {
+		return component{}
+	}
+	defer f.Close()
+
+	var metainfo struct {
+		ID          string `xml:"id"`
+		Launchables []struct {
+			Type string `xml:"type,attr"`
+			ID   string `xml:",chardata"`
+		} `xml:"launchable"`
+	}
+	if err := xml.NewDecoder(f).Decode(&metainfo); err != nil {
+		return component{}
+	}
+	c := component{id: strings.TrimSpace(metainfo.ID)}
+	for _, l := range metainfo.Launchables {
+		if l.Type == "desktop-id" {
+			c.desktopID = strings.TrimSpace(l.ID)
+			break
+		}
+	}
+	if c.desktopID == "" && strings.HasSuffix(c.id, ".desktop") {
+		// before launchables, IDs were desktop IDs
+		c.desktopID = c.id
+	}
+	c.id = strings.TrimSuffix(c.id, ".desktop")
+	if !reverseDNS(c.id) {
+		c.id = ""
+	}
+	return c
+}
+
+// ComponentID returns the component ID of the app with the desktop ID: the one
+// its metainfo file gives, listed in known, else the desktop ID itself when
+// it's in reverse-DNS form, like the ones of Flatpak apps. "" when neither.
+func ComponentID(desktopID string, known map[string]string) string {
+	if id, ok := known[desktopID]; ok {
+		return id
+	}
+	if id := strings.TrimSuffix(desktopID, ".desktop"); reverseDNS(id) {
+		return id
+	}
+	return ""
+}
+
+// Reports whether id looks like "org.mozilla.firefox"
+func reverseDNS(id string) bool {
+	parts := strings.Split(id, ".")
+	if len(parts) < 3 {
+		return false
+	}
+	for _, part := range parts {
+		if part == "" {
+			return false
+		}
+	}
+	return true
+}
+
+// StateIDs maps the desktop IDs of the entries whose state moved to their
+// component ID to it, for Rekey
+func StateIDs(list []DesktopEntry) map[string]string {
+	ids := make(map[string]string)
+	for _, entry := range list {
+		if entry.ComponentID != "" && entry.ComponentID != entry.DesktopID {
+			ids[entry.DesktopID] = entry.ComponentID
+		}
+	}
+	return ids
+}
@@ -0,0 +1,103 @@
+package entries
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestComponentIDs(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"org.mozilla.firefox.appdata.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<component type="desktop-application">
+  <id>org.mozilla.firefox</id>
+  <name>Firefox</name>
+  <launchable type="desktop-id">firefox.desktop</launchable>
+</component>`,
+		// before launchables
+		"org.gnome.gedit.appdata.xml": `<component type="desktop"><id>org.gnome.gedit.desktop</id></component>`,
+		// not reverse-DNS
+		"gimp.appdata.xml": `<component><id>gimp.desktop</id></component>`,
+		"broken.xml":       `<component><id>`,
+	}
+	for name, contents := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	known := ComponentIDs([]string{dir, filepath.Join(dir, "missing")})
+	for desktopID, want := range map[string]string{
+		"firefox.desktop":         "org.mozilla.firefox",
+		"org.gnome.gedit.desktop": "org.gnome.gedit",
+		"gimp.desktop":            "",
+		// Flatpaks are named after their component
+		"org.mozilla.firefox.desktop": "org.mozilla.firefox",
+		"foot.desktop":                "",
+	} {
+		if got := ComponentID(desktopID, known); got != want {
+			t.Errorf("%s: expected %q, got %q", desktopID, want, got)
+		}
+	}
+}
+
+func TestComponentIDsCached(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "foot.xml")
+	ioutil.WriteFile(path, []byte(`<component><id>org.codeberg.dnkl.foot</id><launchable type="desktop-id">foot.desktop</launchable></component>`), 0644)
+	past := time.Now().Add(-time.Hour)
+	os.Chtimes(dir, past, past)
+	if ids := ComponentIDs([]string{dir}); ids["foot.desktop"] != "org.codeberg.dnkl.foot" {
+		t.Fatalf("got %v", ids)
+	}
+
+	// the directory isn't read again while its time stays the same
+	os.Remove(path)
+	os.Chtimes(dir, past, past)
+	if ids := ComponentIDs([]string{dir}); ids["foot.desktop"] != "org.codeberg.dnkl.foot" {
+		t.Errorf("expected the directory not to be read again, got %v", ids)
+	}
+	os.Chtimes(dir, time.Now(), time.Now())
+	if ids := ComponentIDs([]string{dir}); len(ids) != 0 {
+		t.Errorf("expected the removed file to be gone, got %v", ids)
+	}
+}
+
+func TestRekey(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	native := DesktopEntry{DesktopID: "firefox.desktop", ComponentID: "org.mozilla.firefox"}
+	history := History{
+		"firefox.desktop":     {Count: 2, Last: now},
+		"org.mozilla.firefox": {Count: 3, Last: now.Add(-time.Hour)},
+		"foot.desktop":        {Count: 1, Last: now},
+	}
+	if !history.Rekey(StateIDs([]DesktopEntry{native, {DesktopID: "foot.desktop"}})) {
+		t.Error("nothing moved")
+	}
+	if got := history["org.mozilla.firefox"]; got.Count != 5 || !got.Last.Equal(now) {
+		t.Errorf("expected the launches merged, got %+v", got)
+	}
+	if _, ok := history["firefox.desktop"]; ok {
+		t.Error("launches left under the desktop ID")
+	}
+	if history["foot.desktop"].Count != 1 {
+		t.Error("launches of an app without a component ID moved")
+	}
+
+	// the same app moved to Flatpak keeps its launches
+	flatpak := DesktopEntry{DesktopID: "org.mozilla.firefox.desktop", ComponentID: "org.mozilla.firefox"}
+	if got := history.Frecency(flatpak.StateID(), now); got != 5 {
+		t.Errorf("expected the Flatpak ranked by the native launches, got %v", got)
+	}
+
+	seen := FirstSeen{"firefox.desktop": now}
+	if added := seen.Update([]DesktopEntry{native}, now); len(added) != 0 {
+		t.Errorf("expected the native app not to be new, got %v", added)
+	}
+	if added := seen.Update([]DesktopEntry{flatpak}, now.Add(time.Hour)); len(added) != 0 {
+		t.Errorf("expected the Flatpak not to be new, got %v", added)
+	}
+}
@@ -0,0 +1,60 @@
+package entries
+
+import (
+	"os"
+	"strings"
+)
+
+// CurrentDesktops returns the desktop names in XDG_CURRENT_DESKTOP
+func CurrentDesktops() []string {
+	var desktops []string
+	for _, d := range strings.Split(os.Getenv("XDG_CURRENT_DESKTOP"), ":") {
+		if d = strings.TrimSpace(d); d != "" {
+			desktops = append(desktops, d)
+		}
+	}
+	return desktops
+}
+
+// ShownIn tells whether the entry is meant for any of the desktops, going by
+// its OnlyShowIn and NotShowIn keys. With no desktops known all entries are.
+func (entry DesktopEntry) ShownIn(desktops []string) bool {
+	if len(desktops) == 0 {
+		return true
+	}
+	for _, d := range desktops {
+		if listsDesktop(entry.NotShowIn, d) {
+			return false
+		}
+	}
+	if strings.TrimSpace(entry.OnlyShowIn) == "" {
+		return true
+	}
+	for _, d := range desktops {
+		if listsDesktop(entry.OnlyShowIn, d) {
+			return true
+		}
+	}
+	return false
+}
+
+func listsDesktop(list, desktop string) bool {
+	for _, d := range strings.Split(list, ";") {
+		if d = strings.TrimSpace(d); d != "" && strings.EqualFold(d, desktop) {
+			return true
+		}
+	}
+	return false
+}
+
+// ShownInDesktops returns the entries of the list meant for any of the
+// desktops, and the number of the others
+func ShownInDesktops(list []DesktopEntry, desktops []string) ([]DesktopEntry, int) {
+	shown := make([]DesktopEntry, 0, len(list))
+	for _, entry := range list {
+		if entry.ShownIn(desktops) {
+			shown = append(shown, entry)
+		}
+	}
+	return shown, len(list) - len(shown)
+}
@@ -0,0 +1,32 @@
+package entries
+
+import "testing"
+
+func TestShownIn(t *testing.T) {
+	gnome := DesktopEntry{OnlyShowIn: "GNOME;Unity;"}
+	notKDE := DesktopEntry{NotShowIn: "KDE;"}
+	plain := DesktopEntry{}
+
+	for _, tt := range []struct {
+		desktops []string
+		entry    DesktopEntry
+		want     bool
+	}{
+		{[]string{"sway"}, gnome, false},
+		{[]string{"sway"}, notKDE, true},
+		{[]string{"sway"}, plain, true},
+		{[]string{"ubuntu", "GNOME"}, gnome, true},
+		{[]string{"KDE"}, notKDE, false},
+		{nil, gnome, true},
+	} {
+		if got := tt.entry.ShownIn(tt.desktops); got != tt.want {
+			t.Errorf("%+v in %q: got %v", tt.entry, tt.desktops, got)
+		}
+	}
+
+	t.Setenv("XDG_CURRENT_DESKTOP", "sway:wlroots")
+	shown, dropped := ShownInDesktops([]DesktopEntry{gnome, notKDE, plain}, CurrentDesktops())
+	if len(shown) != 2 || dropped != 1 {
+		t.Errorf("expected gnome dropped, got %v", shown)
+	}
+}
@@ -0,0 +1,338 @@
+package entries
+
+import (
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// UserAppDir returns the user's own applications directory, "" if unknown
+func UserAppDir() string {
+	if xdgDataHome := os.Getenv("XDG_DATA_HOME"); xdgDataHome != "" {
+		return filepath.Join(xdgDataHome, "applications")
+	} else if home := os.Getenv("HOME"); home != "" {
+		return filepath.Join(home, ".local/share/applications")
+	}
+	return ""
+}
+
+// ExtraAppDirs are looked for desktop files in after the standard directories
+var ExtraAppDirs []string
+
+// AppDirs returns the directories to look for desktop files in, most
+// important first
+func AppDirs() []string {
+	var dirs []string
+	xdgDataDirs := ""
+
+	home := os.Getenv("HOME")
+	if os.Getenv("XDG_DATA_DIRS") != "" {
+		xdgDataDirs = os.Getenv("XDG_DATA_DIRS")
+	} else {
+		xdgDataDirs = "/usr/local/share/:/usr/share/"
+	}
+	if userDir := UserAppDir(); userDir != "" {
+		dirs = append(dirs, userDir)
+	}
+	for _, d := range strings.Split(xdgDataDirs, ":") {
+		dirs = append(dirs, filepath.Join(d, "applications"))
+	}
+	flatpakDirs := []string{filepath.Join(home, ".local/share/flatpak/exports/share/applications"),
+		"/var/lib/flatpak/exports/share/applications"}
+	// in XDG_DATA_DIRS only for sessions going through snapd's profile script
+	snapDirs := []string{"/var/lib/snapd/desktop/applications"}
+
+	others := append(append(flatpakDirs, snapDirs...), profileDirs(home)...)
+	for _, d := range append(others, ExtraAppDirs...) {
+		if !contains(dirs, d) {
+			dirs = append(dirs, d)
+		}
+	}
+	return dirs
+}
+
+// Returns the applications directories of the Nix and Guix profiles there
+// are. Display managers not set up for them leave them out of XDG_DATA_DIRS.
+func profileDirs(home string) []string {
+	var dirs []string
+	for _, profile := range []string{
+		filepath.Join(home, ".nix-profile"),
+		filepath.Join("/etc/profiles/per-user", os.Getenv("USER")),
+		"/run/current-system/sw",
+		"/nix/var/nix/profiles/default",
+		filepath.Join(home, ".guix-profile"),
+		filepath.Join(home, ".guix-home/profile"),
+		"/run/current-system/profile",
+	} {
+		dir := filepath.Join(profile, "share/applications")
+		if _, err := os.Stat(dir); err == nil {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+// ListDesktopFiles returns paths of the desktop files in AppDirs
+func ListDesktopFiles() []string {
+	var paths []string
+	for _, dir := range AppDirs() {
+		paths = append(paths, listDesktopFiles(dir)...)
+	}
+	return paths
+}
+
+// Returns the desktop files in dir and its subdirectories
+func listDesktopFiles(dir string) []string {
+	var paths []string
+	walkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		// unreadable subdirectories are skipped
+		if err == nil && !d.IsDir() && strings.HasSuffix(d.Name(), ".desktop") {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	return paths
+}
+
+// Walks dir like filepath.WalkDir, but follows dir itself when it is a
+// symlink, as dotfile managers and Nix profiles make them. Paths are given
+// under dir all the same.
+func walkDir(dir string, fn fs.WalkDirFunc) {
+	root, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return
+	}
+	filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if rel, relErr := filepath.Rel(root, path); relErr == nil {
+			path = filepath.Join(dir, rel)
+		}
+		return fn(path, d, err)
+	})
+}
+
+// Returns the desktop ID of the file at path in the applications
+// directory dir: its path from there, with slashes turned into dashes, like
+// "kde4-konsole.desktop" for kde4/konsole.desktop
+func desktopID(dir, path string) string {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return filepath.Base(path)
+	}
+	return strings.ReplaceAll(filepath.ToSlash(rel), "/", "-")
+}
+
+// FindFile returns the path of the desktop file with the desktop ID, looked up
+// in AppDirs like Scan does. The ".desktop" suffix may be left out.
+func FindFile(id string) (string, error) {
+	if !strings.HasSuffix(id, ".desktop") {
+		id += ".desktop"
+	}
+	for _, dir := range AppDirs() {
+		path := filepath.Join(dir, id)
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+		// in a subdirectory, for a vendor prefix
+		if strings.Contains(id, "-") {
+			for _, path := range listDesktopFiles(dir) {
+				if desktopID(dir, path) == id {
+					return path, nil
+				}
+			}
+		}
+	}
+	return "", fmt.Errorf("%s not found", id)
+}
+
+// LastModified returns the latest modification time of AppDirs and their
+// subdirectories, which changes whenever a desktop file is added or removed
+func LastModified() time.Time {
+	var last time.Time
+	for _, dir := range AppDirs() {
+		walkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || !d.IsDir() {
+				return nil
+			}
+			if info, err := d.Info(); err == nil && info.ModTime().After(last) {
+				last = info.ModTime()
+			}
+			return nil
+		})
+	}
+	return last
+}
+
+// DirTimeout is how long Scan waits for a single directory. Directories on
+// hung network mounts would block forever otherwise.
+var DirTimeout = 2 * time.Second
+
+// Scan parses all desktop files. Files with an ID already seen in a more
+// important directory are skipped. Entries with Hidden=true are left out, as
+// are the ones with their ID in less important directories: that's how users
+// delete system entries. Directories not read within DirTimeout are left out
+// and returned as slow.
+func Scan() (desktopEntries []DesktopEntry, slow []string) {
+	generation := nextGeneration()
+	defer pruneParsed(generation)
+	dirs := AppDirs()
+	results := make([]chan []DesktopEntry, len(dirs))
+	for i, dir := range dirs {
+		results[i] = make(chan []DesktopEntry, 1)
+		go func(dir string, result chan<- []DesktopEntry) {
+			var dirEntries []DesktopEntry
+			for _, file := range listDesktopFiles(dir) {
+				entry, err := parseCached(desktopID(dir, file), file, generation)
+				if err == nil {
+					dirEntries = append(dirEntries, entry)
+				}
+			}
+			result <- dirEntries
+		}(dir, results[i])
+	}
+
+	desktopEntries = []DesktopEntry{}
+	id2entry := make(map[string]DesktopEntry)
+	skipped := 0
+	hidden := 0
+	deleted := 0
+	known := ComponentIDs(MetainfoDirs())
+	deadline := time.NewTimer(DirTimeout)
+	defer deadline.Stop()
+	timedOut := false
+	for i, dir := range dirs {
+		var dirEntries []DesktopEntry
+		if !timedOut {
+			select {
+			case dirEntries = <-results[i]:
+			case <-deadline.C:
+				timedOut = true
+			}
+		}
+		if timedOut {
+			// all directories are read in parallel, so after the deadline
+			// the rest are only checked for being done
+			select {
+			case dirEntries = <-results[i]:
+			default:
+				log.Printf("Reading %s timed out, skipping", dir)
+				slow = append(slow, dir)
+				continue
+			}
+		}
+
+		for _, entry := range dirEntries {
+			if _, ok := id2entry[entry.DesktopID]; ok {
+				skipped++
+				continue
+			}
+			id2entry[entry.DesktopID] = entry
+			entry.ComponentID = ComponentID(entry.DesktopID, known)
+
+			if entry.Hidden {
+				deleted++
+				continue
+			}
+			if entry.NoDisplay {
+				hidden++
+				// We still need hidden entries, so `continue` is disallowed here
+				// Fixes introduced in #19
+			}
+
+			desktopEntries = append(desktopEntries, entry)
+		}
+	}
+	log.Printf("Found %v desktop files\n", len(desktopEntries))
+	log.Printf("Skipped %v duplicates and %v deleted by \"Hidden=true\"; %v .desktop entries hidden by \"NoDisplay=true\"", skipped, deleted, hidden)
+	return desktopEntries, slow
+}
+
+// A desktop file as last parsed
+type parsedFile struct {
+	modTime time.Time
+	size    int64
+	id      string
+	entry   DesktopEntry
+	err     error
+	// of the last Scan finding the file
+	generation int
+}
+
+// Desktop files as last parsed: path -> file. Only files changed since are
+// parsed again on rescans, the daemon rescans on every show.
+var (
+	parsedMu    sync.Mutex
+	parsed      = make(map[string]parsedFile)
+	scanCount   int
+	parsedCount int
+	reusedCount int
+)
+
+func nextGeneration() int {
+	parsedMu.Lock()
+	defer parsedMu.Unlock()
+	scanCount++
+	parsedCount, reusedCount = 0, 0
+	return scanCount
+}
+
+// Parses the file unless it is unchanged since the last time
+func parseCached(id, path string, generation int) (DesktopEntry, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return DesktopEntry{}, err
+	}
+	parsedMu.Lock()
+	f, ok := parsed[path]
+	parsedMu.Unlock()
+	reused := ok && f.id == id && f.modTime.Equal(info.ModTime()) && f.size == info.Size()
+	if !reused {
+		f = parsedFile{modTime: info.ModTime(), size: info.Size(), id: id}
+		f.entry, f.err = ParseFile(id, path)
+	}
+	f.generation = generation
+
+	parsedMu.Lock()
+	defer parsedMu.Unlock()
+	parsed[path] = f
+	if reused {
+		reusedCount++
+	} else {
+		parsedCount++
+	}
+	return f.entry, f.err
+}
+
+// Forgets the files the scan didn't find. Ones in directories still being
+// read after the timeout are parsed again next time.
+func pruneParsed(generation int) {
+	parsedMu.Lock()
+	defer parsedMu.Unlock()
+	for path, f := range parsed {
+		if f.generation < generation {
+			delete(parsed, path)
+		}
+	}
+	log.Printf("Parsed %v desktop files, %v unchanged since the last scan", parsedCount, reusedCount)
+}
+
+// Sort sorts entries by localized name
+func Sort(desktopEntries []DesktopEntry) {
+	sort.Slice(desktopEntries, func(i, j int) bool {
+		return desktopEntries[i].NameLoc < desktopEntries[j].NameLoc
+	})
+}
+
+func contains(slice []string, val string) bool {
+	for _, item := range slice {
+		if item == val {
+			return true
+		}
+	}
+	return false
+}
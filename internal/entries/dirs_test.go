@@ -0,0 +1,189 @@
+package entries
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFindFile(t *testing.T) {
+	dataHome := t.TempDir()
+	t.Setenv("XDG_DATA_HOME", dataHome)
+	t.Setenv("XDG_DATA_DIRS", t.TempDir())
+	dir := filepath.Join(dataHome, "applications")
+	os.MkdirAll(dir, 0755)
+	want := filepath.Join(dir, "foo.desktop")
+	if err := ioutil.WriteFile(want, []byte("[Desktop Entry]\nExec=foo\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, id := range []string{"foo", "foo.desktop"} {
+		if path, err := FindFile(id); err != nil || path != want {
+			t.Errorf("FindFile(%q) = %q, %v", id, path, err)
+		}
+	}
+	if _, err := FindFile("bar"); err == nil {
+		t.Error("found a missing file")
+	}
+}
+
+func TestScan(t *testing.T) {
+	dataHome := t.TempDir()
+	dataDir := t.TempDir()
+	t.Setenv("XDG_DATA_HOME", dataHome)
+	t.Setenv("XDG_DATA_DIRS", dataDir)
+	for _, dir := range []string{dataHome, dataDir} {
+		os.MkdirAll(filepath.Join(dir, "applications"), 0755)
+		ioutil.WriteFile(filepath.Join(dir, "applications", "foo.desktop"), []byte("[Desktop Entry]\nName="+dir+"\n"), 0644)
+	}
+
+	list, slow := Scan()
+	if len(slow) != 0 {
+		t.Errorf("unexpected slow directories: %q", slow)
+	}
+	if len(list) != 1 || list[0].Name != dataHome {
+		t.Errorf("expected foo.desktop from the user directory only, got %v", list)
+	}
+}
+
+func TestScanSymlinkedDir(t *testing.T) {
+	dataHome := t.TempDir()
+	t.Setenv("XDG_DATA_HOME", dataHome)
+	t.Setenv("XDG_DATA_DIRS", t.TempDir())
+	real := filepath.Join(t.TempDir(), "dotfiles")
+	os.MkdirAll(filepath.Join(real, "wine"), 0755)
+	ioutil.WriteFile(filepath.Join(real, "foo.desktop"), []byte("[Desktop Entry]\nName=Foo\n"), 0644)
+	ioutil.WriteFile(filepath.Join(real, "wine", "notepad.desktop"), []byte("[Desktop Entry]\nName=Notepad\n"), 0644)
+	dir := filepath.Join(dataHome, "applications")
+	if err := os.Symlink(real, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	list, _ := Scan()
+	if len(list) != 2 {
+		t.Fatalf("expected both files behind the symlink, got %v", list)
+	}
+	for _, entry := range list {
+		if filepath.Dir(entry.Path) != dir && filepath.Dir(entry.Path) != filepath.Join(dir, "wine") {
+			t.Errorf("expected %s under %s", entry.Path, dir)
+		}
+	}
+	if path, err := FindFile("wine-notepad"); err != nil || path != filepath.Join(dir, "wine", "notepad.desktop") {
+		t.Errorf("FindFile = %q, %v", path, err)
+	}
+	if LastModified().IsZero() {
+		t.Error("expected the time of the directory behind the symlink")
+	}
+}
+
+func TestProfileDirs(t *testing.T) {
+	home := t.TempDir()
+	nix := filepath.Join(home, ".nix-profile/share/applications")
+	os.MkdirAll(nix, 0755)
+	os.MkdirAll(filepath.Join(home, ".guix-profile/share"), 0755)
+
+	dirs := profileDirs(home)
+	if !contains(dirs, nix) {
+		t.Errorf("expected %s, got %q", nix, dirs)
+	}
+	if contains(dirs, filepath.Join(home, ".guix-profile/share/applications")) {
+		t.Errorf("expected no missing directories, got %q", dirs)
+	}
+}
+
+func TestScanReusesUnchanged(t *testing.T) {
+	dataHome := t.TempDir()
+	t.Setenv("XDG_DATA_HOME", dataHome)
+	t.Setenv("XDG_DATA_DIRS", t.TempDir())
+	dir := filepath.Join(dataHome, "applications")
+	os.MkdirAll(dir, 0755)
+	path := filepath.Join(dir, "foo.desktop")
+	ioutil.WriteFile(path, []byte("[Desktop Entry]\nName=Foo\n"), 0644)
+	modTime := time.Now().Add(-time.Hour)
+	os.Chtimes(path, modTime, modTime)
+	Scan()
+
+	// same size and time, only the cached entry can be found
+	ioutil.WriteFile(path, []byte("[Desktop Entry]\nName=Bar\n"), 0644)
+	os.Chtimes(path, modTime, modTime)
+	if list, _ := Scan(); len(list) != 1 || list[0].Name != "Foo" {
+		t.Errorf("expected the unchanged file not to be parsed again, got %v", list)
+	}
+
+	os.Chtimes(path, time.Now(), time.Now())
+	if list, _ := Scan(); len(list) != 1 || list[0].Name != "Bar" {
+		t.Errorf("expected the changed file to be parsed again, got %v", list)
+	}
+
+	os.Remove(path)
+	Scan()
+	if _, ok := parsed[path]; ok {
+		t.Error("expected the removed file to be forgotten")
+	}
+}
+
+func TestExtraAppDirs(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	t.Setenv("XDG_DATA_DIRS", t.TempDir())
+	extra := t.TempDir()
+	ioutil.WriteFile(filepath.Join(extra, "backup.desktop"), []byte("[Desktop Entry]\nName=Backup\nExec=backup.sh\n"), 0644)
+	defer func(dirs []string) { ExtraAppDirs = dirs }(ExtraAppDirs)
+	ExtraAppDirs = []string{extra}
+
+	if dirs := AppDirs(); dirs[len(dirs)-1] != extra || dirs[len(dirs)-2] != "/var/lib/snapd/desktop/applications" {
+		t.Errorf("expected snaps', then %s last, got %q", extra, dirs)
+	}
+	if list, _ := Scan(); len(list) != 1 || list[0].DesktopID != "backup.desktop" {
+		t.Errorf("expected backup.desktop, got %v", list)
+	}
+}
+
+func TestScanHidden(t *testing.T) {
+	dataHome := t.TempDir()
+	dataDir := t.TempDir()
+	t.Setenv("XDG_DATA_HOME", dataHome)
+	t.Setenv("XDG_DATA_DIRS", dataDir)
+	for dir, files := range map[string]map[string]string{
+		dataHome: {"deleted.desktop": "Hidden=true\n"},
+		dataDir: {
+			"deleted.desktop":  "Name=Deleted\n",
+			"leftover.desktop": "Name=Leftover\nHidden=true\n",
+			"kept.desktop":     "Name=Kept\n",
+		},
+	} {
+		os.MkdirAll(filepath.Join(dir, "applications"), 0755)
+		for name, contents := range files {
+			ioutil.WriteFile(filepath.Join(dir, "applications", name), []byte("[Desktop Entry]\n"+contents), 0644)
+		}
+	}
+
+	list, _ := Scan()
+	if len(list) != 1 || list[0].DesktopID != "kept.desktop" {
+		t.Errorf("expected only kept.desktop, got %v", list)
+	}
+}
+
+func TestScanSubdirectories(t *testing.T) {
+	dataHome := t.TempDir()
+	t.Setenv("XDG_DATA_HOME", dataHome)
+	t.Setenv("XDG_DATA_DIRS", t.TempDir())
+	dir := filepath.Join(dataHome, "applications")
+	os.MkdirAll(filepath.Join(dir, "wine", "Programs"), 0755)
+	ioutil.WriteFile(filepath.Join(dir, "wine", "Programs", "Notepad++.desktop"), []byte("[Desktop Entry]\nName=Notepad++\nExec=wine notepad++.exe\n"), 0644)
+	ioutil.WriteFile(filepath.Join(dir, "foot.desktop"), []byte("[Desktop Entry]\nName=Foot\nExec=foot\n"), 0644)
+
+	list, _ := Scan()
+	var ids []string
+	for _, entry := range list {
+		ids = append(ids, entry.DesktopID)
+	}
+	if len(ids) != 2 || ids[0] != "foot.desktop" || ids[1] != "wine-Programs-Notepad++.desktop" {
+		t.Errorf("expected foot.desktop and wine-Programs-Notepad++.desktop, got %q", ids)
+	}
+	want := filepath.Join(dir, "wine", "Programs", "Notepad++.desktop")
+	if path, err := FindFile("wine-Programs-Notepad++"); err != nil || path != want {
+		t.Errorf("FindFile = %q, %v", path, err)
+	}
+}
@@ -0,0 +1,50 @@
+package entries
+
+import "strings"
+
+// MatchesPattern reports whether the glob pattern (see MatchGlob) matches the
+// entry's desktop ID, with or without ".desktop", its Exec line or the
+// program it runs, or one of its categories
+func (entry DesktopEntry) MatchesPattern(pattern string) bool {
+	candidates := []string{entry.DesktopID, strings.TrimSuffix(entry.DesktopID, ".desktop")}
+	if entry.Exec != "" {
+		candidates = append(candidates, entry.Exec, binary(entry.Exec))
+	}
+	for _, c := range strings.Split(entry.Category, ";") {
+		if c = strings.TrimSpace(c); c != "" {
+			candidates = append(candidates, c)
+		}
+	}
+	for _, s := range candidates {
+		if MatchGlob(pattern, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// Filter returns the entries of the list matching one of the include
+// patterns, all of them when there are none, but none of the exclude ones,
+// and the number of the others
+func Filter(list []DesktopEntry, include, exclude []string) ([]DesktopEntry, int) {
+	matchesAny := func(entry DesktopEntry, patterns []string) bool {
+		for _, pattern := range patterns {
+			if entry.MatchesPattern(pattern) {
+				return true
+			}
+		}
+		return false
+	}
+
+	kept := make([]DesktopEntry, 0, len(list))
+	for _, entry := range list {
+		if len(include) > 0 && !matchesAny(entry, include) {
+			continue
+		}
+		if matchesAny(entry, exclude) {
+			continue
+		}
+		kept = append(kept, entry)
+	}
+	return kept, len(list) - len(kept)
+}
@@ -0,0 +1,44 @@
+package entries
+
+import "testing"
+
+func TestFilter(t *testing.T) {
+	list := []DesktopEntry{
+		{DesktopID: "avahi-discover.desktop", Exec: "/usr/bin/avahi-discover", Category: "System;Network;"},
+		{DesktopID: "bssh.desktop", Exec: "/usr/bin/bssh --ssh", Category: "Network;"},
+		{DesktopID: "org.gnome.Nautilus.desktop", Exec: "nautilus --new-window %U", Category: "GNOME;Utility;Core;"},
+		{DesktopID: "steam.desktop", Exec: "env GDK_SCALE=1 /usr/bin/steam %U", Category: "Game;"},
+	}
+	ids := func(list []DesktopEntry) []string {
+		var ids []string
+		for _, entry := range list {
+			ids = append(ids, entry.DesktopID)
+		}
+		return ids
+	}
+
+	for _, tt := range []struct {
+		include, exclude []string
+		want             []string
+	}{
+		{nil, nil, []string{"avahi-discover.desktop", "bssh.desktop", "org.gnome.Nautilus.desktop", "steam.desktop"}},
+		{nil, []string{"avahi-discover"}, []string{"bssh.desktop", "org.gnome.Nautilus.desktop", "steam.desktop"}},
+		{nil, []string{"bssh"}, []string{"avahi-discover.desktop", "org.gnome.Nautilus.desktop", "steam.desktop"}}, // by program
+		{nil, []string{"*--new-window*"}, []string{"avahi-discover.desktop", "bssh.desktop", "steam.desktop"}},     // by Exec line
+		{nil, []string{"Network"}, []string{"org.gnome.Nautilus.desktop", "steam.desktop"}},                        // by category
+		{[]string{"Game", "org.gnome.*"}, nil, []string{"org.gnome.Nautilus.desktop", "steam.desktop"}},            // only these
+		{[]string{"Network"}, []string{"bssh.desktop"}, []string{"avahi-discover.desktop"}},                        // excluding wins
+	} {
+		kept, dropped := Filter(list, tt.include, tt.exclude)
+		if got := ids(kept); len(got) != len(tt.want) || dropped != len(list)-len(tt.want) {
+			t.Errorf("include %q, exclude %q: got %v, %d dropped, expected %v", tt.include, tt.exclude, got, dropped, tt.want)
+			continue
+		}
+		for i, id := range ids(kept) {
+			if id != tt.want[i] {
+				t.Errorf("include %q, exclude %q: got %v, expected %v", tt.include, tt.exclude, ids(kept), tt.want)
+				break
+			}
+		}
+	}
+}
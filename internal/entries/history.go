@@ -0,0 +1,167 @@
+package entries
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ftphikari/wlaunchpad/internal/config"
+)
+
+// Launches of an entry
+type Launches struct {
+	Count int
+	Last  time.Time
+}
+
+// History counts launches of entries: state ID (see DesktopEntry.StateID) ->
+// launches
+type History map[string]Launches
+
+// Format changes of the saved history, see config.Migrations
+var historyMigrations = config.Migrations{}
+
+// LoadHistory reads the history saved at path. A missing file gives an empty
+// history.
+func LoadHistory(path string) (History, error) {
+	history := make(History)
+	if err := historyMigrations.Migrate(path); err != nil {
+		return history, err
+	}
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return history, nil
+	} else if err != nil {
+		return history, err
+	}
+	defer f.Close()
+
+	// "<count> <unix time of the last launch> <state ID>" per line
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.SplitN(scanner.Text(), " ", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		count, err := strconv.Atoi(fields[0])
+		if err != nil {
+			continue
+		}
+		last, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		history[fields[2]] = Launches{Count: count, Last: time.Unix(last, 0)}
+	}
+	return history, scanner.Err()
+}
+
+// Save writes the history to path
+func (history History) Save(path string) error {
+	ids := make([]string, 0, len(history))
+	for id := range history {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	var b strings.Builder
+	for _, id := range ids {
+		fmt.Fprintf(&b, "%d %d %s\n", history[id].Count, history[id].Last.Unix(), id)
+	}
+	return config.WriteFile(path, []byte(b.String()), 0644)
+}
+
+// Record counts a launch of the entry
+func (history History) Record(id string, now time.Time) {
+	launches := history[id]
+	launches.Count++
+	launches.Last = now
+	history[id] = launches
+}
+
+// Rekey moves the launches saved under desktop IDs to the IDs they map to, see
+// StateIDs. Returns whether anything moved.
+func (history History) Rekey(ids map[string]string) bool {
+	moved := false
+	for old, id := range ids {
+		launches, ok := history[old]
+		if !ok {
+			continue
+		}
+		merged := history[id]
+		merged.Count += launches.Count
+		if launches.Last.After(merged.Last) {
+			merged.Last = launches.Last
+		}
+		history[id] = merged
+		delete(history, old)
+		moved = true
+	}
+	return moved
+}
+
+// Frecency scores the entry by how often and how recently it was launched
+func (history History) Frecency(id string, now time.Time) float64 {
+	launches, ok := history[id]
+	if !ok {
+		return 0
+	}
+
+	age := now.Sub(launches.Last)
+	day := 24 * time.Hour
+	weight := 0.1
+	switch {
+	case age < 4*day:
+		weight = 1
+	case age < 14*day:
+		weight = 0.7
+	case age < 31*day:
+		weight = 0.5
+	case age < 90*day:
+		weight = 0.3
+	}
+	return float64(launches.Count) * weight
+}
+
+// Recent returns up to n visible entries of the list launched last, the last
+// first
+func (history History) Recent(list []DesktopEntry, n int) []DesktopEntry {
+	var launched []DesktopEntry
+	for _, entry := range list {
+		if _, ok := history[entry.StateID()]; ok && !entry.NoDisplay {
+			launched = append(launched, entry)
+		}
+	}
+	sort.SliceStable(launched, func(i, j int) bool {
+		return history[launched[i].StateID()].Last.After(history[launched[j].StateID()].Last)
+	})
+	if len(launched) > n {
+		launched = launched[:n]
+	}
+	return launched
+}
+
+// Top returns up to n visible entries of the list with the highest frecency
+func (history History) Top(list []DesktopEntry, n int, now time.Time) []DesktopEntry {
+	return Top(history, list, n, now)
+}
+
+// Top returns up to n visible entries of the list ranked highest
+func Top(r Ranker, list []DesktopEntry, n int, now time.Time) []DesktopEntry {
+	var launched []DesktopEntry
+	for _, entry := range list {
+		if !entry.NoDisplay && r.Frecency(entry.StateID(), now) > 0 {
+			launched = append(launched, entry)
+		}
+	}
+	sort.SliceStable(launched, func(i, j int) bool {
+		return r.Frecency(launched[i].StateID(), now) > r.Frecency(launched[j].StateID(), now)
+	})
+	if len(launched) > n {
+		launched = launched[:n]
+	}
+	return launched
+}
@@ -0,0 +1,58 @@
+package entries
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history")
+	history, err := LoadHistory(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Unix(1700000000, 0)
+	month := 30 * 24 * time.Hour
+	for i := 0; i < 5; i++ {
+		history.Record("old.desktop", now.Add(-3*month))
+	}
+	history.Record("recent.desktop", now)
+	history.Record("recent.desktop", now)
+	history.Record("hidden.desktop", now)
+	if err := history.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	history, err = LoadHistory(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if history["old.desktop"].Count != 5 || !history["recent.desktop"].Last.Equal(now) {
+		t.Errorf("history not restored: %+v", history)
+	}
+
+	list := []DesktopEntry{
+		{DesktopID: "never.desktop"},
+		{DesktopID: "old.desktop"},
+		{DesktopID: "hidden.desktop", NoDisplay: true},
+		{DesktopID: "recent.desktop"},
+	}
+	top := history.Top(list, 5, now)
+	if len(top) != 2 || top[0].DesktopID != "recent.desktop" || top[1].DesktopID != "old.desktop" {
+		t.Errorf("unexpected order: %v", top)
+	}
+	if top := history.Top(list, 1, now); len(top) != 1 {
+		t.Errorf("expected 1 entry, got %v", top)
+	}
+
+	// old.desktop was launched more often, but longer ago
+	if recent := history.Recent(list, 5); len(recent) != 2 || recent[0].DesktopID != "recent.desktop" || recent[1].DesktopID != "old.desktop" {
+		t.Errorf("unexpected order: %v", recent)
+	}
+	history.Record("old.desktop", now.Add(time.Minute))
+	if recent := history.Recent(list, 1); len(recent) != 1 || recent[0].DesktopID != "old.desktop" {
+		t.Errorf("expected old.desktop, launched last, got %v", recent)
+	}
+}
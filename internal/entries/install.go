@@ -0,0 +1,42 @@
+package entries
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ftphikari/wlaunchpad/internal/config"
+)
+
+// Install copies the desktop file at path into dir, the user's applications
+// directory, and returns the path of the copy. The copy is executable: file
+// managers and desktops only trust launchers which are. Invalid entries, and
+// names taken in dir already, are refused.
+func Install(path, dir string) (string, error) {
+	if !strings.HasSuffix(path, ".desktop") {
+		return "", fmt.Errorf("%s is not a desktop file", filepath.Base(path))
+	}
+	entry, err := ParseFile(filepath.Base(path), path)
+	if err != nil {
+		return "", err
+	}
+	if err := Validate(entry); err != nil {
+		return "", fmt.Errorf("%s is invalid: %s", entry.DesktopID, err)
+	}
+
+	dest := filepath.Join(dir, entry.DesktopID)
+	if _, err := os.Stat(dest); err == nil {
+		return "", fmt.Errorf("%s is installed already", entry.DesktopID)
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	if err := config.WriteFile(dest, data, 0755); err != nil {
+		return "", err
+	}
+	// not left to the umask
+	return dest, os.Chmod(dest, 0755)
+}
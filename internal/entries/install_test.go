@@ -0,0 +1,42 @@
+package entries
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestInstall(t *testing.T) {
+	downloads := t.TempDir()
+	apps := filepath.Join(t.TempDir(), "applications")
+	write := func(name, contents string) string {
+		path := filepath.Join(downloads, name)
+		if err := ioutil.WriteFile(path, []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	valid := write("tool.desktop", "[Desktop Entry]\nType=Application\nName=Tool\nExec=tool %U\n")
+	dest, err := Install(valid, apps)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dest != filepath.Join(apps, "tool.desktop") {
+		t.Errorf("installed to %s", dest)
+	}
+	if info, err := os.Stat(dest); err != nil || info.Mode().Perm() != 0755 {
+		t.Errorf("expected an executable copy, got %v, %v", info, err)
+	}
+
+	if _, err := Install(valid, apps); err == nil {
+		t.Error("overwrote an installed entry")
+	}
+	if _, err := Install(write("games.desktop", "[Desktop Entry]\nType=Directory\nName=Games\n"), apps); err == nil {
+		t.Error("installed an entry which can't be launched")
+	}
+	if _, err := Install(write("notes.txt", "[Desktop Entry]\n"), apps); err == nil {
+		t.Error("installed a file which isn't a desktop file")
+	}
+}
@@ -0,0 +1,35 @@
+package entries
+
+import "strings"
+
+// Keys of the US QWERTY layout, row by row
+const qwerty = "qwertyuiop[]asdfghjkl;'zxcvbnm,."
+
+// Characters the same keys type in other layouts
+var layouts = map[string]string{
+	"ru": "йцукенгшщзхъфывапролджэячсмитьбю",
+	"ua": "йцукенгшщзхїфівапролджєячсмитьбю",
+}
+
+// Retype returns s as it would have been typed on the QWERTY layout, for
+// queries typed with the wrong layout active: "ашкуащч" -> "firefox". ok is
+// false for unknown layouts and for strings with nothing to retype.
+func Retype(s, layout string) (retyped string, ok bool) {
+	keys, known := layouts[layout]
+	if !known {
+		return s, false
+	}
+
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		if i := strings.IndexRune(keys, r); i != -1 {
+			// both strings are indexed by rune, qwerty is ASCII
+			i = len([]rune(keys[:i]))
+			b.WriteByte(qwerty[i])
+			ok = true
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String(), ok
+}
@@ -0,0 +1,23 @@
+package entries
+
+import "testing"
+
+func TestRetype(t *testing.T) {
+	for _, tt := range []struct {
+		s, layout, want string
+		ok              bool
+	}{
+		{"ашкуащч", "ru", "firefox", true},
+		{"Ашку", "ru", "fire", true},
+		{"шьфпу", "ua", "image", true},
+		{"іеуфь", "ua", "steam", true},
+		{"штлысфзу", "ru", "inkscape", true},
+		{"firefox", "ru", "firefox", false},
+		{"ашкуащч", "xx", "ашкуащч", false},
+	} {
+		got, ok := Retype(tt.s, tt.layout)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("Retype(%q, %q) = %q, %v; expected %q, %v", tt.s, tt.layout, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package entries
+
+import "strings"
+
+// Returns the locale suffixes of localized keys matching the locale, best
+// first, as in the Desktop Entry spec: "sr_YU.UTF-8@Latn" gives sr_YU@Latn,
+// sr_YU, sr@Latn and sr
+func localeNames(locale string) []string {
+	var modifier string
+	if i := strings.IndexByte(locale, '@'); i != -1 {
+		locale, modifier = locale[:i], locale[i:]
+	}
+	if i := strings.IndexByte(locale, '.'); i != -1 {
+		locale = locale[:i]
+	}
+	if locale == "" || locale == "C" || locale == "POSIX" {
+		return nil
+	}
+	lang, country := locale, ""
+	if i := strings.IndexByte(locale, '_'); i != -1 {
+		lang, country = locale[:i], locale[i:]
+	}
+
+	var names []string
+	if country != "" && modifier != "" {
+		names = append(names, lang+country+modifier)
+	}
+	if country != "" {
+		names = append(names, lang+country)
+	}
+	if modifier != "" {
+		names = append(names, lang+modifier)
+	}
+	return append(names, lang)
+}
+
+// Splits "Name[pt_BR]" into "Name" and "pt_BR"
+func splitLocale(key string) (string, string) {
+	i := strings.IndexByte(key, '[')
+	if i == -1 || !strings.HasSuffix(key, "]") {
+		return key, ""
+	}
+	return key[:i], key[i+1 : len(key)-1]
+}
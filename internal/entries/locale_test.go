@@ -0,0 +1,21 @@
+package entries
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLocaleNames(t *testing.T) {
+	for locale, want := range map[string][]string{
+		"sr_YU.UTF-8@Latn": {"sr_YU@Latn", "sr_YU", "sr@Latn", "sr"},
+		"pt_BR.UTF-8":      {"pt_BR", "pt"},
+		"de@euro":          {"de@euro", "de"},
+		"pt":               {"pt"},
+		"C.UTF-8":          nil,
+		"":                 nil,
+	} {
+		if got := localeNames(locale); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: got %q, expected %q", locale, got, want)
+		}
+	}
+}
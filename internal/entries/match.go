@@ -0,0 +1,172 @@
+package entries
+
+import "strings"
+
+// MatchGlob reports whether s matches a shell-like pattern, where * matches
+// any sequence of characters (slashes included) and ? any single character.
+// It runs for every entry and pattern on every scan, so patterns aren't
+// compiled: a * is retried one character further on each mismatch after it.
+func MatchGlob(pattern, s string) bool {
+	p, r := []rune(pattern), []rune(s)
+	pi, si := 0, 0
+	star, mark := -1, 0
+	for si < len(r) {
+		switch {
+		case pi < len(p) && p[pi] == '*':
+			star, mark = pi, si
+			pi++
+		case pi < len(p) && (p[pi] == '?' || p[pi] == r[si]):
+			pi++
+			si++
+		case star != -1:
+			mark++
+			pi, si = star+1, mark
+		default:
+			return false
+		}
+	}
+	for pi < len(p) && p[pi] == '*' {
+		pi++
+	}
+	return pi == len(p)
+}
+
+// Fields selects the fields of entries a search looks at
+type Fields struct {
+	Names, Comments, Keywords, Commands bool
+	// Fuzzy finds phrases no field contains too, when each of their words
+	// is in a field, or its letters are in order in a name or keyword:
+	// "gimp ed", "chrm"
+	Fuzzy bool
+}
+
+// Match tells where a search phrase was found in an entry
+type Match struct {
+	Field  string // "Name", "GenericName", "Comment", "Keywords" or "Exec"
+	Phrase string // lowercased, maybe retyped from another keyboard layout
+	Pos    int    // byte offset in the lowercased field
+	// Fuzzy matches found the words of Phrase apart, Field and Pos tell
+	// where the first one is. Gaps counts the letters skipped between the
+	// letters of words, fewer is closer.
+	Fuzzy bool
+	Gaps  int
+}
+
+type field struct {
+	name     string
+	selected bool
+	value    string
+}
+
+// Returns the fields in the order matches in them rank
+func (f Fields) fields(entry DesktopEntry) []field {
+	return []field{
+		{"Name", f.Names, entry.NameLoc},
+		{"GenericName", f.Names, entry.GenericNameLoc},
+		{"GenericName", f.Names, entry.GenericName},
+		{"Keywords", f.Keywords, entry.KeywordsLoc},
+		{"Keywords", f.Keywords, entry.Keywords},
+		{"Comment", f.Comments, entry.CommentLoc},
+		{"Comment", f.Comments, entry.Comment},
+		{"Exec", f.Commands, entry.Exec},
+	}
+}
+
+// Find returns where the first of the phrases found in the selected fields of
+// the entry is, ignoring case. Fuzzy matches are looked for only when none of
+// the phrases is found as typed.
+func (f Fields) Find(entry DesktopEntry, phrases []string) (Match, bool) {
+	fields := f.fields(entry)
+	for _, phrase := range phrases {
+		phrase = strings.ToLower(phrase)
+		for _, field := range fields {
+			if !field.selected {
+				continue
+			}
+			if pos := strings.Index(strings.ToLower(field.value), phrase); pos != -1 {
+				return Match{Field: field.name, Phrase: phrase, Pos: pos}, true
+			}
+		}
+	}
+	if !f.Fuzzy {
+		return Match{}, false
+	}
+	for _, phrase := range phrases {
+		if m, ok := findFuzzy(fields, strings.ToLower(phrase)); ok {
+			return m, true
+		}
+	}
+	return Match{}, false
+}
+
+// Finds each word of the phrase in the fields, where it is as typed or else
+// where its letters are closest together
+func findFuzzy(fields []field, phrase string) (Match, bool) {
+	words := strings.Fields(phrase)
+	if len(words) == 0 {
+		return Match{}, false
+	}
+	m := Match{Phrase: phrase, Fuzzy: true}
+	for i, word := range words {
+		best := Match{Gaps: -1}
+		for _, field := range fields {
+			if !field.selected {
+				continue
+			}
+			value := strings.ToLower(field.value)
+			pos, gaps := strings.Index(value, word), 0
+			if pos == -1 && (field.name == "Name" || field.name == "GenericName" || field.name == "Keywords") {
+				// descriptions and commands are long enough to have the
+				// letters of most words somewhere
+				pos, gaps = subsequence(value, word)
+			}
+			if pos != -1 && (best.Gaps == -1 || gaps < best.Gaps) {
+				best = Match{Field: field.name, Pos: pos, Gaps: gaps}
+			}
+		}
+		if best.Gaps == -1 {
+			return Match{}, false
+		}
+		if i == 0 {
+			m.Field, m.Pos = best.Field, best.Pos
+		}
+		m.Gaps += best.Gaps
+	}
+	return m, true
+}
+
+// Returns where the letters of word are in s in order, closest together, and
+// the number of other letters between them. -1 if they aren't all there.
+func subsequence(s, word string) (pos, gaps int) {
+	runes, letters := []rune(s), []rune(word)
+	pos, gaps = -1, -1
+	for start := range runes {
+		if runes[start] != letters[0] {
+			continue
+		}
+		i, n := start, 0
+		for ; i < len(runes) && n < len(letters); i++ {
+			if runes[i] == letters[n] {
+				n++
+			}
+		}
+		if n < len(letters) {
+			break
+		}
+		if span := i - start - len(letters); gaps == -1 || span < gaps {
+			pos, gaps = len(string(runes[:start])), span
+		}
+	}
+	return pos, gaps
+}
+
+// InCategory reports whether the entry lists the category in its Categories
+// key, ignoring case
+func (entry DesktopEntry) InCategory(category string) bool {
+	for _, c := range strings.Split(entry.Category, ";") {
+		if c = strings.TrimSpace(c); c != "" && strings.EqualFold(c, category) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,100 @@
+package entries
+
+import "testing"
+
+func TestMatchGlob(t *testing.T) {
+	for _, c := range []struct {
+		pattern, s string
+		match      bool
+	}{
+		{"rm *", "rm -rf /tmp/x", true},
+		{"*poweroff*", "systemctl poweroff", true},
+		{"dd *", "add something", false},
+		{"org.gnome.*.desktop", "org.gnome.Nautilus.desktop", true},
+		{"fire?ox", "firefox", true},
+		{"fire.ox", "firefox", false},
+		{"*", "", true},
+		{"a*b*c", "aXbYbZc", true},
+		{"a*b?c", "abc", false},
+		{"*.desktop", "kde4-konsole.desktop.bak", false},
+		{"épée*", "épée long", true},
+	} {
+		if MatchGlob(c.pattern, c.s) != c.match {
+			t.Errorf("MatchGlob(%q, %q) != %v", c.pattern, c.s, c.match)
+		}
+	}
+}
+
+func TestFind(t *testing.T) {
+	entry := DesktopEntry{NameLoc: "Firefox", Comment: "Browse the Web", Exec: "firefox %u"}
+	all := Fields{Names: true, Comments: true, Keywords: true, Commands: true}
+
+	if m, ok := all.Find(entry, []string{"web"}); !ok || m.Field != "Comment" || m.Pos != 11 {
+		t.Errorf("expected web at 11 in Comment, got %+v, %v", m, ok)
+	}
+	if m, ok := all.Find(entry, []string{"ашкуащч", "FOX"}); !ok || m.Field != "Name" || m.Phrase != "fox" {
+		t.Errorf("expected fox in Name, got %+v, %v", m, ok)
+	}
+	entry.Keywords = "Internet;WWW;Web;"
+	if m, ok := all.Find(entry, []string{"web"}); !ok || m.Field != "Keywords" {
+		t.Errorf("expected web in Keywords before Comment, got %+v, %v", m, ok)
+	}
+	entry.GenericNameLoc = "Web Browser"
+	if m, ok := (Fields{Names: true}).Find(entry, []string{"browser"}); !ok || m.Field != "GenericName" {
+		t.Errorf("expected browser in GenericName, got %+v, %v", m, ok)
+	}
+	if _, ok := (Fields{Names: true}).Find(entry, []string{"%u"}); ok {
+		t.Error("matched a field not selected")
+	}
+}
+
+func TestFindFuzzy(t *testing.T) {
+	gimp := DesktopEntry{NameLoc: "GNU Image Manipulation Program", GenericNameLoc: "Image Editor", Exec: "gimp-2.10 %U"}
+	chromium := DesktopEntry{NameLoc: "Chromium", Comment: "Access the Internet"}
+	all := Fields{Names: true, Comments: true, Keywords: true, Commands: true, Fuzzy: true}
+
+	if m, ok := all.Find(gimp, []string{"gimp ed"}); !ok || !m.Fuzzy || m.Field != "Exec" || m.Gaps != 0 {
+		t.Errorf("expected gimp in Exec and ed elsewhere, got %+v, %v", m, ok)
+	}
+	if m, ok := all.Find(chromium, []string{"chrm"}); !ok || m.Field != "Name" || m.Pos != 0 || m.Gaps != 1 {
+		t.Errorf("expected chrm in Name with a gap, got %+v, %v", m, ok)
+	}
+	if m, ok := all.Find(chromium, []string{"chrom"}); !ok || m.Fuzzy {
+		t.Errorf("expected an exact match, got %+v, %v", m, ok)
+	}
+	if _, ok := all.Find(chromium, []string{"atnt"}); ok {
+		t.Error("matched letters of a comment")
+	}
+	all.Fuzzy = false
+	if _, ok := all.Find(chromium, []string{"chrm"}); ok {
+		t.Error("matched fuzzily without Fuzzy")
+	}
+}
+
+func TestSubsequence(t *testing.T) {
+	for _, c := range []struct {
+		s, word   string
+		pos, gaps int
+	}{
+		{"chromium", "chrm", 0, 1},
+		{"google chrome", "chrm", 7, 1},
+		{"cxhxrxm chrxm", "chrm", 8, 1},
+		{"thunderbird", "tbd", 0, 8},
+		{"éclair", "élr", 0, 3},
+		{"files", "sf", -1, -1},
+	} {
+		if pos, gaps := subsequence(c.s, c.word); pos != c.pos || gaps != c.gaps {
+			t.Errorf("subsequence(%q, %q) = %d, %d, want %d, %d", c.s, c.word, pos, gaps, c.pos, c.gaps)
+		}
+	}
+}
+
+func TestInCategory(t *testing.T) {
+	entry := DesktopEntry{Category: "Game;ArcadeGame;"}
+	if !entry.InCategory("arcadegame") {
+		t.Error("category not found")
+	}
+	if entry.InCategory("Arcade") || entry.InCategory("") {
+		t.Error("matched a category not listed")
+	}
+}
@@ -0,0 +1,114 @@
+package entries
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/ftphikari/wlaunchpad/internal/launch"
+)
+
+// Merge collapses variants of the same app, like terminal emulator profiles,
+// into one entry. Each group lists desktop ID glob patterns; the entry matching
+// the earliest pattern stays and the others become its Alternatives. With
+// sameBinary, visible entries running the same binary are merged as well,
+// keeping the one with the shortest name.
+func Merge(list []DesktopEntry, groups [][]string, sameBinary bool) []DesktopEntry {
+	// index of an entry in list -> index of the entry it's merged into
+	mergedInto := make(map[int]int)
+
+	for _, patterns := range groups {
+		primary := -1
+		var members []int
+		for _, pattern := range patterns {
+			for i, entry := range list {
+				if _, ok := mergedInto[i]; ok || i == primary || entry.NoDisplay || containsInt(members, i) {
+					continue
+				}
+				if MatchGlob(pattern, entry.DesktopID) {
+					if primary == -1 {
+						primary = i
+					} else {
+						members = append(members, i)
+					}
+				}
+			}
+		}
+		for _, i := range members {
+			mergedInto[i] = primary
+		}
+	}
+
+	if sameBinary {
+		byBinary := make(map[string]int)
+		for i, entry := range list {
+			if _, ok := mergedInto[i]; ok || entry.NoDisplay || entry.Exec == "" || isPrimary(mergedInto, i) {
+				continue
+			}
+			bin := binary(entry.Exec)
+			primary, ok := byBinary[bin]
+			if !ok {
+				byBinary[bin] = i
+				continue
+			}
+			if len(entry.NameLoc) < len(list[primary].NameLoc) {
+				primary, i = i, primary
+				byBinary[bin] = primary
+				// entries merged into the previous primary move along
+				for j, into := range mergedInto {
+					if into == i {
+						mergedInto[j] = primary
+					}
+				}
+			}
+			mergedInto[i] = primary
+		}
+	}
+
+	var merged []DesktopEntry
+	for i, entry := range list {
+		if _, ok := mergedInto[i]; ok {
+			continue
+		}
+		for j := range list {
+			if into, ok := mergedInto[j]; ok && into == i {
+				entry.Alternatives = append(entry.Alternatives, list[j])
+			}
+		}
+		merged = append(merged, entry)
+	}
+	return merged
+}
+
+func isPrimary(mergedInto map[int]int, i int) bool {
+	for _, into := range mergedInto {
+		if into == i {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the name of the binary an Exec line runs, skipping env and
+// environment variables
+func binary(exec string) string {
+	fields, err := launch.Split(exec)
+	if err != nil {
+		fields = strings.Fields(exec)
+	}
+	for _, field := range fields {
+		if field == "env" || strings.Contains(field, "=") || strings.HasPrefix(field, "-") {
+			continue
+		}
+		return filepath.Base(field)
+	}
+	return ""
+}
+
+func containsInt(slice []int, val int) bool {
+	for _, item := range slice {
+		if item == val {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,47 @@
+package entries
+
+import "testing"
+
+func TestMerge(t *testing.T) {
+	list := []DesktopEntry{
+		{DesktopID: "Alacritty-wayland.desktop", NameLoc: "Alacritty (Wayland)", Exec: "env WINIT_UNIX_BACKEND=wayland alacritty"},
+		{DesktopID: "Alacritty.desktop", NameLoc: "Alacritty", Exec: "alacritty"},
+		{DesktopID: "foot.desktop", NameLoc: "Foot", Exec: "foot"},
+		{DesktopID: "foot-server.desktop", NameLoc: "Foot Server", Exec: "foot --server"},
+		{DesktopID: "footclient.desktop", NameLoc: "Foot Client", Exec: "footclient"},
+	}
+
+	merged := Merge(list, [][]string{{"Alacritty.desktop", "Alacritty-*.desktop"}}, false)
+	if len(merged) != 4 || merged[0].DesktopID != "Alacritty.desktop" {
+		t.Fatalf("unexpected result of merge rules: %v", merged)
+	}
+	if alts := merged[0].Alternatives; len(alts) != 1 || alts[0].DesktopID != "Alacritty-wayland.desktop" {
+		t.Errorf("unexpected alternatives: %v", alts)
+	}
+
+	merged = Merge(list, nil, true)
+	if len(merged) != 3 {
+		t.Fatalf("expected 3 entries merging by binary, got %v", merged)
+	}
+	if merged[0].DesktopID != "Alacritty.desktop" || merged[1].DesktopID != "foot.desktop" {
+		t.Errorf("expected the shortest names to stay, got %v", merged)
+	}
+	if alts := merged[1].Alternatives; len(alts) != 1 || alts[0].DesktopID != "foot-server.desktop" {
+		t.Errorf("unexpected alternatives: %v", alts)
+	}
+}
+
+func TestMergeOverlappingPatterns(t *testing.T) {
+	list := []DesktopEntry{
+		{DesktopID: "foot.desktop", NameLoc: "Foot"},
+		{DesktopID: "foot-server.desktop", NameLoc: "Foot Server"},
+	}
+
+	merged := Merge(list, [][]string{{"foot.desktop", "foot*.desktop"}}, false)
+	if len(merged) != 1 || merged[0].DesktopID != "foot.desktop" {
+		t.Fatalf("unexpected result of merge rules: %v", merged)
+	}
+	if alts := merged[0].Alternatives; len(alts) != 1 || alts[0].DesktopID != "foot-server.desktop" {
+		t.Errorf("unexpected alternatives: %v", alts)
+	}
+}
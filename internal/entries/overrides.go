@@ -0,0 +1,147 @@
+package entries
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/ftphikari/wlaunchpad/internal/config"
+)
+
+// Override changes how an entry is shown, without editing its desktop file
+type Override struct {
+	// shown instead of the entry's name and searched for, "" keeps it
+	Name string
+	// icon name or image file, "" keeps the entry's
+	Icon string
+	// replaces the entry's Exec line, "" keeps it
+	Exec   string
+	Hidden bool
+}
+
+// Overrides maps the IDs of entries to their Override: desktop IDs, with or
+// without ".desktop", or component IDs
+type Overrides map[string]Override
+
+// LoadOverrides reads the overrides file at path, in the config file format,
+// with a section per entry:
+//
+//	[org.gnome.Nautilus]
+//	name = "Files"
+//
+//	[avahi-discover]
+//	hidden = true
+//
+//	[code]
+//	exec = "code --ozone-platform=wayland %F"
+//
+// A missing file gives no overrides.
+func LoadOverrides(path string) (Overrides, error) {
+	c, err := config.Migrations{}.Load(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	overrides := make(Overrides)
+	for id := range c {
+		if id == "" {
+			continue
+		}
+		overrides[strings.TrimSuffix(id, ".desktop")] = Override{
+			Name:   c.Str(id, "name", ""),
+			Icon:   os.ExpandEnv(c.Str(id, "icon", "")),
+			Exec:   c.Str(id, "exec", ""),
+			Hidden: c.Bool(id, "hidden", false),
+		}
+	}
+	return overrides, nil
+}
+
+// Apply returns the list with the overrides applied
+func (overrides Overrides) Apply(list []DesktopEntry) []DesktopEntry {
+	if len(overrides) == 0 {
+		return list
+	}
+	applied := make([]DesktopEntry, 0, len(list))
+	for _, entry := range list {
+		if o, ok := overrides.lookup(entry); ok {
+			if o.Name != "" {
+				entry.Name, entry.NameLoc = o.Name, o.Name
+			}
+			if o.Icon != "" {
+				entry.Icon = o.Icon
+			}
+			if o.Exec != "" {
+				entry.Exec = o.Exec
+			}
+			if o.Hidden {
+				entry.NoDisplay = true
+			}
+		}
+		applied = append(applied, entry)
+	}
+	return applied
+}
+
+func (overrides Overrides) lookup(entry DesktopEntry) (Override, bool) {
+	if o, ok := overrides[strings.TrimSuffix(entry.DesktopID, ".desktop")]; ok {
+		return o, true
+	}
+	if entry.ComponentID != "" {
+		o, ok := overrides[entry.ComponentID]
+		return o, ok
+	}
+	return Override{}, false
+}
+
+// SetOverride sets a key of the section of id in the overrides file at path,
+// keeping the rest of the file as it is. The section is added if missing, its
+// header may have ".desktop" or not.
+func SetOverride(path, id, key, value string) error {
+	contents, err := ioutil.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	id = strings.TrimSuffix(id, ".desktop")
+	line := fmt.Sprintf("%s = %s", key, strconv.Quote(value))
+	if strings.Contains(value, `"`) && !strings.Contains(value, "'") {
+		// comments are only told apart from quoted text without escapes
+		line = fmt.Sprintf("%s = '%s'", key, value)
+	}
+
+	lines := strings.Split(strings.TrimRight(string(contents), "\n"), "\n")
+	if len(contents) == 0 {
+		lines = nil
+	}
+	header := -1
+	for i, l := range lines {
+		l = strings.TrimSpace(l)
+		if strings.HasPrefix(l, "[") {
+			if header != -1 {
+				break
+			}
+			if name := strings.TrimSpace(strings.Trim(l, "[]")); strings.TrimSuffix(name, ".desktop") == id {
+				header = i
+			}
+			continue
+		}
+		if header != -1 && strings.TrimSpace(strings.SplitN(l, "=", 2)[0]) == key {
+			lines[i] = line
+			return config.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644)
+		}
+	}
+
+	if header == -1 {
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, "["+id+"]", line)
+	} else {
+		lines = append(lines[:header+1], append([]string{line}, lines[header+1:]...)...)
+	}
+	return config.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644)
+}
@@ -0,0 +1,92 @@
+package entries
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestOverrides(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "overrides.toml")
+	if overrides, err := LoadOverrides(path); err != nil || overrides != nil {
+		t.Fatalf("missing file: got %v, %v", overrides, err)
+	}
+
+	t.Setenv("HOME", "/home/me")
+	ioutil.WriteFile(path, []byte(`[org.gnome.Nautilus.desktop]
+name = "Files"
+
+[org.mozilla.firefox]
+icon = "$HOME/icons/firefox.png"
+exec = "env MOZ_ENABLE_WAYLAND=1 firefox %u"
+
+[avahi-discover]
+hidden = true
+`), 0644)
+	overrides, err := LoadOverrides(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	list := overrides.Apply([]DesktopEntry{
+		{DesktopID: "org.gnome.Nautilus.desktop", Name: "Nautilus", NameLoc: "Nautilus", Icon: "org.gnome.Nautilus"},
+		{DesktopID: "firefox.desktop", ComponentID: "org.mozilla.firefox", NameLoc: "Firefox", Icon: "firefox", Exec: "firefox %u"},
+		{DesktopID: "avahi-discover.desktop", NameLoc: "Avahi Zeroconf Browser"},
+		{DesktopID: "foot.desktop", NameLoc: "Foot", Icon: "foot"},
+	})
+	if e := list[0]; e.Name != "Files" || e.NameLoc != "Files" || e.Icon != "org.gnome.Nautilus" {
+		t.Errorf("not renamed: %+v", e)
+	}
+	if e := list[1]; e.Icon != "/home/me/icons/firefox.png" || e.NameLoc != "Firefox" || e.Exec != "env MOZ_ENABLE_WAYLAND=1 firefox %u" {
+		t.Errorf("icon not overridden by component ID: %+v", e)
+	}
+	if !list[2].NoDisplay {
+		t.Error("avahi-discover.desktop not hidden")
+	}
+	if e := list[3]; e.NameLoc != "Foot" || e.Icon != "foot" || e.NoDisplay {
+		t.Errorf("entry without overrides changed: %+v", e)
+	}
+}
+
+func TestSetOverride(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "overrides.toml")
+	if err := SetOverride(path, "code.desktop", "exec", "code --ozone-platform=wayland %F"); err != nil {
+		t.Fatal(err)
+	}
+	ioutil.WriteFile(path, []byte(`# renamed
+[org.gnome.Nautilus.desktop]
+name = "Files"
+
+[code]
+exec = "code"
+hidden = false
+`), 0644)
+
+	if err := SetOverride(path, "code.desktop", "exec", `bash -c "code # wayland"`); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetOverride(path, "org.gnome.Nautilus", "exec", "nautilus --new-window"); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetOverride(path, "foot", "exec", "foot"); err != nil {
+		t.Fatal(err)
+	}
+
+	overrides, err := LoadOverrides(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if o := overrides["code"]; o.Exec != `bash -c "code # wayland"` || o.Hidden {
+		t.Errorf("code: got %+v", o)
+	}
+	if o := overrides["org.gnome.Nautilus"]; o.Exec != "nautilus --new-window" || o.Name != "Files" {
+		t.Errorf("org.gnome.Nautilus: got %+v", o)
+	}
+	if o := overrides["foot"]; o.Exec != "foot" {
+		t.Errorf("foot: got %+v", o)
+	}
+	if contents, _ := ioutil.ReadFile(path); !strings.HasPrefix(string(contents), "# renamed\n") {
+		t.Errorf("comment lost: %q", contents)
+	}
+}
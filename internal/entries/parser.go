@@ -0,0 +1,256 @@
+// Package entries finds and parses freedesktop.org desktop entries.
+package entries
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// DesktopEntry is an application found in a .desktop file, or a synthetic
+// entry provided by the launcher itself
+type DesktopEntry struct {
+	DesktopID string
+	// Reverse-DNS AppStream ID, like "org.mozilla.firefox", the same for
+	// the distribution's package and the Flatpak. Empty if unknown, see
+	// ComponentID.
+	ComponentID string
+	Type        string
+	Name        string
+	NameLoc     string
+	// "Web Browser" for Firefox
+	GenericName    string
+	GenericNameLoc string
+	Comment        string
+	CommentLoc     string
+	// Keywords are separated by semicolons, as in the desktop file
+	Keywords    string
+	KeywordsLoc string
+	Icon        string
+	Exec        string
+	// Program to check for before showing the entry, see Installed
+	TryExec   string
+	Category  string
+	Terminal  bool
+	NoDisplay bool
+	// The app tells when it has started, see the startup notification spec
+	StartupNotify bool
+	// Dual-GPU machines should start the app on the discrete GPU
+	PrefersNonDefaultGPU bool
+	// Hidden=true means the entry was deleted, see Scan
+	Hidden bool
+	// Desktops separated by semicolons, see ShownIn
+	OnlyShowIn string
+	NotShowIn  string
+	// Path of the desktop file, empty for synthetic entries
+	Path string
+	// Working directory to start the program in, the Path key
+	WorkingDir string
+	// Address a Type=Link entry opens, Exec opens it with xdg-open
+	URL string
+	// Variants merged into this entry, see Merge
+	Alternatives []DesktopEntry
+	// Additional launch targets, like "New Private Window"
+	Actions []DesktopAction
+	// Action replaces launching Exec for synthetic entries
+	Action func()
+	// Provider is the name of the source of the entry, "apps" for desktop
+	// files
+	Provider string
+}
+
+// DesktopAction is a [Desktop Action ...] group of a desktop file
+type DesktopAction struct {
+	ID      string
+	Name    string
+	NameLoc string
+	Icon    string
+	Exec    string
+}
+
+// ParseFile parses the desktop file at path
+func ParseFile(id string, path string) (e DesktopEntry, err error) {
+	o, err := os.Open(path)
+	if err != nil {
+		return e, err
+	}
+	defer o.Close()
+
+	e, err = Parse(id, o)
+	e.Path = path
+	return e, err
+}
+
+// Parse parses the [Desktop Entry] group of a desktop file and the actions
+// listed in it
+func Parse(id string, in io.Reader) (entry DesktopEntry, err error) {
+	entry.DesktopID = id
+	locales := localeNames(os.Getenv("LANG"))
+	// localized value -> index in locales of the one set
+	best := make(map[*string]int)
+	scanner := bufio.NewScanner(in)
+	scanner.Split(bufio.ScanLines)
+
+	var listed []string
+	actions := make(map[string]*DesktopAction)
+	// nil in the [Desktop Entry] group, and in groups we don't know
+	var action *DesktopAction
+	group := ""
+	for scanner.Scan() {
+		l := scanner.Text()
+		if header := strings.TrimSpace(l); strings.HasPrefix(header, "[") && strings.HasSuffix(header, "]") {
+			group = header[1 : len(header)-1]
+			action = nil
+			if id := strings.TrimPrefix(group, "Desktop Action "); id != group {
+				action = &DesktopAction{ID: id}
+				actions[id] = action
+			}
+			continue
+		}
+
+		name, value := parseKeypair(l)
+		if value == "" {
+			continue
+		}
+
+		if key, locale := splitLocale(name); locale != "" {
+			var localized *string
+			if action != nil {
+				if key == "Name" {
+					localized = &action.NameLoc
+				}
+			} else if group == "Desktop Entry" || group == "" {
+				switch key {
+				case "Name":
+					localized = &entry.NameLoc
+				case "GenericName":
+					localized = &entry.GenericNameLoc
+				case "Comment":
+					localized = &entry.CommentLoc
+				case "Keywords":
+					localized = &entry.KeywordsLoc
+				}
+			}
+			if localized == nil {
+				continue
+			}
+			for rank, l := range locales {
+				if l != locale {
+					continue
+				}
+				if previous, ok := best[localized]; !ok || rank < previous {
+					*localized = value
+					best[localized] = rank
+				}
+				break
+			}
+			continue
+		}
+
+		if action != nil {
+			switch name {
+			case "Name":
+				action.Name = value
+			case "Icon":
+				action.Icon = value
+			case "Exec":
+				action.Exec = unescape(value)
+			}
+			continue
+		}
+		if group != "Desktop Entry" && group != "" {
+			continue
+		}
+
+		switch name {
+		case "Type":
+			entry.Type = value
+		case "Name":
+			entry.Name = value
+		case "GenericName":
+			entry.GenericName = value
+		case "Comment":
+			entry.Comment = value
+		case "Keywords":
+			entry.Keywords = value
+		case "Icon":
+			entry.Icon = value
+		case "Categories":
+			entry.Category = value
+		case "Terminal":
+			entry.Terminal, _ = strconv.ParseBool(value)
+		case "NoDisplay":
+			entry.NoDisplay, _ = strconv.ParseBool(value)
+		case "StartupNotify":
+			entry.StartupNotify, _ = strconv.ParseBool(value)
+		case "PrefersNonDefaultGPU":
+			entry.PrefersNonDefaultGPU, _ = strconv.ParseBool(value)
+		case "Hidden":
+			entry.Hidden, _ = strconv.ParseBool(value)
+		case "OnlyShowIn":
+			entry.OnlyShowIn = value
+		case "NotShowIn":
+			entry.NotShowIn = value
+		case "Exec":
+			entry.Exec = unescape(value)
+		case "TryExec":
+			entry.TryExec = value
+		case "Path":
+			entry.WorkingDir = value
+		case "URL":
+			entry.URL = unescape(value)
+		case "Actions":
+			listed = strings.Split(value, ";")
+		}
+	}
+
+	// only the listed actions count, in that order
+	for _, id := range listed {
+		a, ok := actions[strings.TrimSpace(id)]
+		if !ok || a.Exec == "" {
+			continue
+		}
+		if a.NameLoc == "" {
+			a.NameLoc = a.Name
+		}
+		entry.Actions = append(entry.Actions, *a)
+	}
+
+	if entry.Type == "Link" && entry.URL != "" {
+		entry.Exec = linkExec(entry.URL)
+	}
+	if entry.NameLoc == "" {
+		entry.NameLoc = entry.Name
+	}
+	if entry.GenericNameLoc == "" {
+		entry.GenericNameLoc = entry.GenericName
+	}
+	if entry.CommentLoc == "" {
+		entry.CommentLoc = entry.Comment
+	}
+	if entry.KeywordsLoc == "" {
+		entry.KeywordsLoc = entry.Keywords
+	}
+	return entry, err
+}
+
+func parseKeypair(s string) (string, string) {
+	if idx := strings.IndexRune(s, '='); idx > 0 {
+		return strings.TrimSpace(s[:idx]), strings.TrimSpace(s[idx+1:])
+	}
+	return s, ""
+}
+
+// Returns the Exec line opening the URL of a link: quoted, with the reserved
+// characters escaped and percent signs doubled, as they'd start field codes
+func linkExec(url string) string {
+	return `xdg-open "` + linkEscape.Replace(url) + `"`
+}
+
+var linkEscape = strings.NewReplacer(`"`, `\"`, "`", "\\`", `$`, `\$`, `\`, `\\`, `%`, `%%`)
+
+// Undoes the escapes of string values: \s, \n, \t, \r and \\. Exec lines
+// have their own quoting on top, see launch.Split.
+var unescape = strings.NewReplacer(`\s`, " ", `\n`, "\n", `\t`, "\t", `\r`, "\r", `\\`, `\`).Replace
@@ -0,0 +1,195 @@
+package entries
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestWhitespaceHandling(t *testing.T) {
+	const whitespace = `[Desktop Entry]
+	Categories = Debugger; Development; Git; IDE; Programming; TextEditor; 
+	Comment = Editor for building and debugging modern web and cloud applications
+	Exec = bash -c "code-insiders ~/Workspaces/Linux/Flutter.code-workspace"
+	GenericName = Text Editor
+	Icon = vscode-flutter
+	Keywords = editor; IDE; plaintext; text; write; 
+	MimeType = application/x-shellscript; inode/directory; text/english; text/plain; text/x-c; text/x-c++; text/x-c++hdr; text/x-c++src; text/x-chdr; text/x-csrc; text/x-java; text/x-makefile; text/x-moc; text/x-pascal; text/x-tcl; text/x-tex; 
+	Name = VSCode Insiders with Flutter
+	Name[pt] = VSCode Insiders com Flutter
+	StartupNotify = true
+	StartupWMClass = code - insiders
+	Terminal = false
+	NoDisplay = false
+	Type = Application
+	Version = 1.0`
+
+	os.Setenv("LANG", "pt") // Portuguese
+	entry, err := Parse("id", strings.NewReader(whitespace))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if entry.Name != "VSCode Insiders with Flutter" {
+		t.Error("failed to parse desktop entry name")
+	}
+
+	if entry.NameLoc != "VSCode Insiders com Flutter" {
+		t.Error("failed to parse localized name")
+	}
+
+	if entry.NoDisplay {
+		t.Error("failed to parse desktop entry no display")
+	}
+}
+
+func TestParseActions(t *testing.T) {
+	const firefox = `[Desktop Entry]
+Name=Firefox
+Exec=firefox %u
+Actions=new-window;new-private-window;missing;
+
+[Desktop Action new-private-window]
+Name=New Private Window
+Name[pt]=Nova janela privada
+Exec=firefox --private-window %u
+
+[Desktop Action new-window]
+Name=New Window
+Exec=firefox --new-window %u
+
+[Desktop Action unlisted]
+Name=Unlisted
+Exec=firefox --unlisted
+`
+
+	os.Setenv("LANG", "pt")
+	entry, err := Parse("firefox.desktop", strings.NewReader(firefox))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry.Exec != "firefox %u" {
+		t.Errorf("Exec overwritten by an action: %q", entry.Exec)
+	}
+	if len(entry.Actions) != 2 {
+		t.Fatalf("expected the 2 listed actions, got %+v", entry.Actions)
+	}
+	if a := entry.Actions[0]; a.ID != "new-window" || a.NameLoc != "New Window" || a.Exec != "firefox --new-window %u" {
+		t.Errorf("got %+v", a)
+	}
+	if a := entry.Actions[1]; a.ID != "new-private-window" || a.NameLoc != "Nova janela privada" {
+		t.Errorf("got %+v", a)
+	}
+}
+
+func TestParseLocalized(t *testing.T) {
+	const file = `[Desktop Entry]
+Name=Web Browser
+Name[pt_BR]=Navegador
+Name[pt]=Navegador Web
+Keywords=browser;internet;
+Keywords[pt]=navegador;
+Keywords[pt_BR]=navegador;internet;
+Comment[de]=Im Internet surfen
+`
+
+	os.Setenv("LANG", "pt_BR.UTF-8")
+	entry, err := Parse("browser.desktop", strings.NewReader(file))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry.NameLoc != "Navegador" {
+		t.Errorf("expected the pt_BR name, got %q", entry.NameLoc)
+	}
+	if entry.KeywordsLoc != "navegador;internet;" {
+		t.Errorf("expected the pt_BR keywords, got %q", entry.KeywordsLoc)
+	}
+	if entry.CommentLoc != "" {
+		t.Errorf("expected no comment, got %q", entry.CommentLoc)
+	}
+}
+
+func TestParseLink(t *testing.T) {
+	const link = "[Desktop Entry]\nType=Link\nName=Docs\nURL=https://example.com/?q=100%\n"
+
+	entry, err := Parse("docs.desktop", strings.NewReader(link))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry.URL != "https://example.com/?q=100%" {
+		t.Errorf("failed to parse URL, got %q", entry.URL)
+	}
+	if want := `xdg-open "https://example.com/?q=100%%"`; entry.Exec != want {
+		t.Errorf("expected Exec %q, got %q", want, entry.Exec)
+	}
+	if err := Validate(entry); err != nil {
+		t.Errorf("link not valid: %s", err)
+	}
+}
+
+func TestWorkingDir(t *testing.T) {
+	const game = "[Desktop Entry]\nName=Game\nExec=./start.sh\nPath=/opt/game\n"
+
+	entry, err := Parse("game.desktop", strings.NewReader(game))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry.WorkingDir != "/opt/game" {
+		t.Errorf("failed to parse the working directory, got %q", entry.WorkingDir)
+	}
+	if entry.Path != "" {
+		t.Errorf("Path key taken for the desktop file path: %q", entry.Path)
+	}
+}
+
+func TestPrefersNonDefaultGPU(t *testing.T) {
+	const game = "[Desktop Entry]\nName=Game\nExec=game\nPrefersNonDefaultGPU=true\n"
+
+	entry, err := Parse("game.desktop", strings.NewReader(game))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !entry.PrefersNonDefaultGPU {
+		t.Error("failed to parse prefers non-default GPU")
+	}
+}
+
+func TestParseKeywords(t *testing.T) {
+	const editor = "[Desktop Entry]\nName=Editor\nKeywords = editor; text; write; \n"
+
+	entry, err := Parse("editor.desktop", strings.NewReader(editor))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry.Keywords != "editor; text; write;" || entry.KeywordsLoc != entry.Keywords {
+		t.Errorf("failed to parse keywords, got %q and %q", entry.Keywords, entry.KeywordsLoc)
+	}
+}
+
+func TestParseExecQuotes(t *testing.T) {
+	// the string escape \\ goes, the quoting of Exec stays for launch.Split
+	const script = `[Desktop Entry]
+Name=Script
+Exec=bash -c "echo \\"hi\\"; sleep 1"
+`
+
+	entry, err := Parse("script.desktop", strings.NewReader(script))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `bash -c "echo \"hi\"; sleep 1"`; entry.Exec != want {
+		t.Errorf("failed to keep the quotes of Exec, got %q, want %q", entry.Exec, want)
+	}
+}
+
+func TestParseStartupNotify(t *testing.T) {
+	const app = "[Desktop Entry]\nName=App\nExec=app\nStartupNotify=true\n"
+
+	entry, err := Parse("app.desktop", strings.NewReader(app))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !entry.StartupNotify {
+		t.Error("failed to parse startup notify")
+	}
+}
@@ -0,0 +1,84 @@
+package entries
+
+import (
+	"bufio"
+	"os"
+	"strings"
+
+	"github.com/ftphikari/wlaunchpad/internal/config"
+)
+
+// Pins are the state IDs (see DesktopEntry.StateID) of the pinned entries, in
+// the order they're shown
+type Pins []string
+
+// LoadPins reads the pins saved at path, one ID per line. A missing file gives
+// no pins.
+func LoadPins(path string) (Pins, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var pins Pins
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if id := strings.TrimSpace(scanner.Text()); id != "" && !pins.Contains(id) {
+			pins = append(pins, id)
+		}
+	}
+	return pins, scanner.Err()
+}
+
+// Save writes the pins to path
+func (pins Pins) Save(path string) error {
+	var b strings.Builder
+	for _, id := range pins {
+		b.WriteString(id + "\n")
+	}
+	return config.WriteFile(path, []byte(b.String()), 0644)
+}
+
+// Contains reports whether the ID is pinned
+func (pins Pins) Contains(id string) bool {
+	for _, pinned := range pins {
+		if pinned == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Add returns the pins with the ID pinned last
+func (pins Pins) Add(id string) Pins {
+	if pins.Contains(id) {
+		return pins
+	}
+	return append(pins, id)
+}
+
+// Remove returns the pins without the ID
+func (pins Pins) Remove(id string) Pins {
+	var kept Pins
+	for _, pinned := range pins {
+		if pinned != id {
+			kept = append(kept, pinned)
+		}
+	}
+	return kept
+}
+
+// Entries returns the pinned entries of the snapshot, in order. Pinned apps
+// which aren't installed are left out, and come back once they are.
+func (pins Pins) Entries(s *Snapshot) []DesktopEntry {
+	var list []DesktopEntry
+	for _, id := range pins {
+		if entry, ok := s.Lookup(id); ok {
+			list = append(list, entry)
+		}
+	}
+	return list
+}
@@ -0,0 +1,40 @@
+package entries
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestPins(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data", "pinned")
+	pins, err := LoadPins(path)
+	if err != nil || len(pins) != 0 {
+		t.Fatalf("expected no pins, got %v, %v", pins, err)
+	}
+
+	pins = pins.Add("org.mozilla.firefox").Add("foot.desktop").Add("gone.desktop").Add("foot.desktop")
+	if err := pins.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	pins, err = LoadPins(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pins) != 3 || pins[0] != "org.mozilla.firefox" || pins[1] != "foot.desktop" {
+		t.Errorf("pins not restored in order: %v", pins)
+	}
+
+	s := NewSnapshot([]DesktopEntry{
+		{DesktopID: "foot.desktop", NameLoc: "Foot"},
+		{DesktopID: "org.mozilla.firefox.desktop", ComponentID: "org.mozilla.firefox", NameLoc: "Firefox"},
+	})
+	list := pins.Entries(s)
+	if len(list) != 2 || list[0].NameLoc != "Firefox" || list[1].NameLoc != "Foot" {
+		t.Errorf("expected Firefox and Foot, got %v", list)
+	}
+
+	pins = pins.Remove("org.mozilla.firefox")
+	if pins.Contains("org.mozilla.firefox") || !pins.Contains("foot.desktop") {
+		t.Errorf("unpinning failed: %v", pins)
+	}
+}
@@ -0,0 +1,53 @@
+package entries
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// Section is where the names starting with a letter begin in a list
+type Section struct {
+	Letter rune
+	Start  int
+}
+
+// SectionLetter returns the letter a name is filed under: its first letter in
+// upper case, '#' for names starting with anything else
+func SectionLetter(name string) rune {
+	r, _ := utf8.DecodeRuneInString(name)
+	if !unicode.IsLetter(r) {
+		return '#'
+	}
+	return unicode.ToUpper(r)
+}
+
+// Sections returns the sections of a list sorted by name, in list order. A
+// letter showing up again further down starts no new section.
+func Sections(list []DesktopEntry) []Section {
+	var sections []Section
+	seen := make(map[rune]bool)
+	for i, entry := range list {
+		letter := SectionLetter(entry.NameLoc)
+		if !seen[letter] {
+			seen[letter] = true
+			sections = append(sections, Section{letter, i})
+		}
+	}
+	return sections
+}
+
+// SectionFor returns the section of the letter, or the one of the nearest
+// letter after it when no names start with it. ok is false when there's
+// neither.
+func SectionFor(sections []Section, r rune) (section Section, ok bool) {
+	letter := unicode.ToUpper(r)
+	for _, s := range sections {
+		if s.Letter == '#' || s.Letter < letter {
+			continue
+		}
+		if !ok || s.Letter < section.Letter {
+			section, ok = s, true
+		}
+	}
+	return section, ok
+}
@@ -0,0 +1,37 @@
+package entries
+
+import "testing"
+
+func TestSections(t *testing.T) {
+	var list []DesktopEntry
+	for _, name := range []string{"0 A.D.", "Audacity", "avidemux", "Calculator", "dolphin", "firefox"} {
+		list = append(list, DesktopEntry{NameLoc: name})
+	}
+	sections := Sections(list)
+	want := []Section{{'#', 0}, {'A', 1}, {'C', 3}, {'D', 4}, {'F', 5}}
+	if len(sections) != len(want) {
+		t.Fatalf("got %v, expected %v", sections, want)
+	}
+	for i := range want {
+		if sections[i] != want[i] {
+			t.Errorf("section %d: got %v, expected %v", i, sections[i], want[i])
+		}
+	}
+
+	for _, tt := range []struct {
+		r     rune
+		start int
+		ok    bool
+	}{
+		{'a', 1, true},
+		{'C', 3, true},
+		{'b', 3, true}, // none start with B, C comes next
+		{'f', 5, true},
+		{'z', 0, false},
+	} {
+		s, ok := SectionFor(sections, tt.r)
+		if ok != tt.ok || ok && s.Start != tt.start {
+			t.Errorf("SectionFor(%q) = %v, %v, expected start %d, %v", tt.r, s, ok, tt.start, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package entries
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ftphikari/wlaunchpad/internal/config"
+)
+
+// FirstSeen records when desktop entries were found for the first time, to
+// tell newly installed apps apart: state ID (see DesktopEntry.StateID) -> time
+type FirstSeen map[string]time.Time
+
+// Format changes of the saved record, see config.Migrations
+var firstSeenMigrations = config.Migrations{
+	// 0 -> 1: unversioned files need no changes
+	func(contents string) string { return contents },
+}
+
+// LoadFirstSeen reads the record saved at path. A missing file gives an empty
+// record.
+func LoadFirstSeen(path string) (FirstSeen, error) {
+	seen := make(FirstSeen)
+	if err := firstSeenMigrations.Migrate(path); err != nil {
+		return seen, err
+	}
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return seen, nil
+	} else if err != nil {
+		return seen, err
+	}
+	defer f.Close()
+
+	// "<unix time> <state ID>" per line
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.SplitN(scanner.Text(), " ", 2)
+		if len(fields) != 2 {
+			continue
+		}
+		t, err := strconv.ParseInt(fields[0], 10, 64)
+		if err != nil {
+			continue
+		}
+		seen[fields[1]] = time.Unix(t, 0)
+	}
+	return seen, scanner.Err()
+}
+
+// Save writes the record to path
+func (seen FirstSeen) Save(path string) error {
+	ids := make([]string, 0, len(seen))
+	for id := range seen {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	var b strings.Builder
+	fmt.Fprintf(&b, "version = %d\n", firstSeenMigrations.Version())
+	for _, id := range ids {
+		fmt.Fprintf(&b, "%d %s\n", seen[id].Unix(), id)
+	}
+
+	return config.WriteFile(path, []byte(b.String()), 0644)
+}
+
+// Update records the visible entries not seen before and returns them. Entries
+// gone since are forgotten, so a reinstalled app is new again, unless it comes
+// back with the same component ID, as when moving to the Flatpak. On the first
+// run everything is recorded and nothing returned: apps installed before
+// aren't new.
+func (seen FirstSeen) Update(list []DesktopEntry, now time.Time) []DesktopEntry {
+	firstRun := len(seen) == 0
+
+	var added []DesktopEntry
+	present := make(map[string]bool)
+	for _, entry := range list {
+		if entry.NoDisplay {
+			continue
+		}
+		id := entry.StateID()
+		if t, ok := seen[entry.DesktopID]; ok && id != entry.DesktopID {
+			// saved before the app had a component ID
+			delete(seen, entry.DesktopID)
+			if earlier, ok := seen[id]; !ok || t.Before(earlier) {
+				seen[id] = t
+			}
+		}
+		present[id] = true
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = now
+		if !firstRun {
+			added = append(added, entry)
+		}
+	}
+	for id := range seen {
+		if !present[id] {
+			delete(seen, id)
+		}
+	}
+	return added
+}
@@ -0,0 +1,44 @@
+package entries
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFirstSeen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "first-seen")
+	seen, err := LoadFirstSeen(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Unix(1700000000, 0)
+	installed := []DesktopEntry{{DesktopID: "firefox.desktop"}, {DesktopID: "hidden.desktop", NoDisplay: true}}
+	if added := seen.Update(installed, start); len(added) != 0 {
+		t.Errorf("first run reported %v as new", added)
+	}
+	if err := seen.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	seen, err = LoadFirstSeen(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !seen["firefox.desktop"].Equal(start) {
+		t.Errorf("expected firefox.desktop seen at %v, got %v", start, seen["firefox.desktop"])
+	}
+	if _, ok := seen["hidden.desktop"]; ok {
+		t.Error("recorded a hidden entry")
+	}
+
+	later := start.Add(time.Hour)
+	added := seen.Update([]DesktopEntry{{DesktopID: "krita.desktop"}}, later)
+	if len(added) != 1 || added[0].DesktopID != "krita.desktop" {
+		t.Errorf("expected krita.desktop to be new, got %v", added)
+	}
+	if _, ok := seen["firefox.desktop"]; ok {
+		t.Error("an uninstalled app is still recorded")
+	}
+}
@@ -0,0 +1,57 @@
+package entries
+
+// Snapshot is an immutable list of entries, sorted by name, with an index by
+// desktop ID and component ID. A rescan builds a new one and swaps it in
+// whole, so searching and rendering never see a half-built list.
+type Snapshot struct {
+	entries []DesktopEntry
+	byID    map[string]int
+	// component IDs of the entries with one
+	byComponent map[string]int
+	hidden      int
+}
+
+// NewSnapshot returns a snapshot of a copy of the list
+func NewSnapshot(list []DesktopEntry) *Snapshot {
+	s := &Snapshot{
+		entries:     append([]DesktopEntry(nil), list...),
+		byID:        make(map[string]int, len(list)),
+		byComponent: make(map[string]int),
+	}
+	Sort(s.entries)
+	for i, entry := range s.entries {
+		if _, ok := s.byID[entry.DesktopID]; !ok {
+			s.byID[entry.DesktopID] = i
+		}
+		if _, ok := s.byComponent[entry.ComponentID]; !ok && entry.ComponentID != "" {
+			s.byComponent[entry.ComponentID] = i
+		}
+		if entry.NoDisplay {
+			s.hidden++
+		}
+	}
+	return s
+}
+
+// Entries returns the entries sorted by name. The slice is shared and must
+// not be modified.
+func (s *Snapshot) Entries() []DesktopEntry {
+	return s.entries
+}
+
+// Lookup returns the entry with the desktop ID, or else the component ID
+func (s *Snapshot) Lookup(id string) (DesktopEntry, bool) {
+	i, ok := s.byID[id]
+	if !ok {
+		i, ok = s.byComponent[id]
+	}
+	if !ok {
+		return DesktopEntry{}, false
+	}
+	return s.entries[i], true
+}
+
+// Hidden returns the number of NoDisplay entries
+func (s *Snapshot) Hidden() int {
+	return s.hidden
+}
@@ -0,0 +1,25 @@
+package entries
+
+import "testing"
+
+func TestSnapshot(t *testing.T) {
+	list := []DesktopEntry{
+		{DesktopID: "b.desktop", NameLoc: "B"},
+		{DesktopID: "a.desktop", NameLoc: "A", NoDisplay: true},
+	}
+	s := NewSnapshot(list)
+	list[0].NameLoc = "changed"
+
+	if got := s.Entries(); len(got) != 2 || got[0].NameLoc != "A" || got[1].NameLoc != "B" {
+		t.Errorf("expected a sorted copy, got %v", got)
+	}
+	if entry, ok := s.Lookup("b.desktop"); !ok || entry.NameLoc != "B" {
+		t.Errorf("lookup failed, got %v, %v", entry, ok)
+	}
+	if _, ok := s.Lookup("c.desktop"); ok {
+		t.Error("found an entry not in the snapshot")
+	}
+	if s.Hidden() != 1 {
+		t.Errorf("expected 1 hidden entry, got %d", s.Hidden())
+	}
+}
@@ -0,0 +1,114 @@
+package entries
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// Sorter is a strategy ordering the entries shown in a view
+type Sorter interface {
+	Sort(list []DesktopEntry)
+}
+
+// Alphabetical sorts entries by localized name
+type Alphabetical struct{}
+
+func (Alphabetical) Sort(list []DesktopEntry) {
+	sort.SliceStable(list, func(i, j int) bool {
+		return list[i].NameLoc < list[j].NameLoc
+	})
+}
+
+// ByFrecency puts the most used entries first, the rest by name
+type ByFrecency struct {
+	Ranker Ranker
+	Now    time.Time
+}
+
+func (s ByFrecency) Sort(list []DesktopEntry) {
+	sort.SliceStable(list, func(i, j int) bool {
+		fi, fj := s.Ranker.Frecency(list[i].StateID(), s.Now), s.Ranker.Frecency(list[j].StateID(), s.Now)
+		if fi != fj {
+			return fi > fj
+		}
+		return list[i].NameLoc < list[j].NameLoc
+	})
+}
+
+// Manual puts the entries with the listed desktop IDs first, in that order,
+// the rest by name
+type Manual struct {
+	Order []string
+}
+
+func (s Manual) Sort(list []DesktopEntry) {
+	rank := make(map[string]int, len(s.Order))
+	for i, id := range s.Order {
+		if _, ok := rank[id]; !ok {
+			rank[id] = i
+		}
+	}
+	sort.SliceStable(list, func(i, j int) bool {
+		ri, iListed := rank[list[i].DesktopID]
+		rj, jListed := rank[list[j].DesktopID]
+		switch {
+		case iListed && jListed:
+			return ri < rj
+		case iListed != jListed:
+			return iListed
+		}
+		return list[i].NameLoc < list[j].NameLoc
+	})
+}
+
+// ByCategory groups entries by their first category, then sorts them by name.
+// Entries without one come last.
+type ByCategory struct{}
+
+func (ByCategory) Sort(list []DesktopEntry) {
+	sort.SliceStable(list, func(i, j int) bool {
+		ci, cj := firstCategory(list[i]), firstCategory(list[j])
+		if ci != cj {
+			return ci != "" && (cj == "" || ci < cj)
+		}
+		return list[i].NameLoc < list[j].NameLoc
+	})
+}
+
+func firstCategory(entry DesktopEntry) string {
+	for _, c := range strings.Split(entry.Category, ";") {
+		if c = strings.TrimSpace(c); c != "" {
+			return c
+		}
+	}
+	return ""
+}
+
+// ByScore sorts entries by a score, lowest first, then by frecency when
+// there's a Ranker, then by name
+type ByScore struct {
+	Score  func(DesktopEntry) int
+	Ranker Ranker
+	Now    time.Time
+}
+
+func (s ByScore) Sort(list []DesktopEntry) {
+	scores := make(map[string]int, len(list))
+	for _, entry := range list {
+		scores[entry.DesktopID] = s.Score(entry)
+	}
+	sort.SliceStable(list, func(i, j int) bool {
+		si, sj := scores[list[i].DesktopID], scores[list[j].DesktopID]
+		if si != sj {
+			return si < sj
+		}
+		if s.Ranker != nil {
+			fi, fj := s.Ranker.Frecency(list[i].StateID(), s.Now), s.Ranker.Frecency(list[j].StateID(), s.Now)
+			if fi != fj {
+				return fi > fj
+			}
+		}
+		return list[i].NameLoc < list[j].NameLoc
+	})
+}
@@ -0,0 +1,48 @@
+package entries
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSorters(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	history := History{}
+	history.Record("b.desktop", now)
+	history.Record("c.desktop", now)
+	history.Record("c.desktop", now)
+
+	for _, tt := range []struct {
+		name   string
+		sorter Sorter
+		want   string
+	}{
+		{"alphabetical", Alphabetical{}, "a b c d"},
+		{"frecency", ByFrecency{history, now}, "c b a d"},
+		{"manual", Manual{[]string{"d.desktop", "b.desktop", "x.desktop"}}, "d b a c"},
+		{"category", ByCategory{}, "c a d b"},
+		{"score", ByScore{Score: func(e DesktopEntry) int { return len(e.Category) }}, "b d a c"},
+		{"score then frecency", ByScore{Score: func(e DesktopEntry) int {
+			if e.Category != "" {
+				return 1
+			}
+			return 0
+		}, Ranker: history, Now: now}, "b c a d"},
+	} {
+		list := []DesktopEntry{
+			{DesktopID: "d.desktop", NameLoc: "d", Category: "Game;"},
+			{DesktopID: "c.desktop", NameLoc: "c", Category: "Audio;Video;"},
+			{DesktopID: "b.desktop", NameLoc: "b"},
+			{DesktopID: "a.desktop", NameLoc: "a", Category: ";Game;"},
+		}
+		tt.sorter.Sort(list)
+		var names []string
+		for _, entry := range list {
+			names = append(names, entry.NameLoc)
+		}
+		if got := strings.Join(names, " "); got != tt.want {
+			t.Errorf("%s: got %q, expected %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package entries
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AppendUsage appends a launch of the entry to the usage log at path, one
+// "<unix time>\t<desktop ID>" line per launch, for other frecency tools
+func AppendUsage(path, id string, now time.Time) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(f, "%d\t%s\n", now.Unix(), id)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// Scores are frecency scores from other launchers: desktop ID -> score
+type Scores map[string]float64
+
+// LoadScores reads "<score> <desktop ID>" lines, as printed by zoxide-style
+// tools. Lines which aren't are skipped.
+func LoadScores(path string) (Scores, error) {
+	scores := make(Scores)
+	f, err := os.Open(path)
+	if err != nil {
+		return scores, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		score, err := strconv.ParseFloat(fields[0], 64)
+		if err != nil || score < 0 {
+			continue
+		}
+		scores[fields[1]] += score
+	}
+	return scores, scanner.Err()
+}
+
+// Rekey moves the scores of desktop IDs to the IDs they map to, see StateIDs
+func (scores Scores) Rekey(ids map[string]string) {
+	for old, id := range ids {
+		if score, ok := scores[old]; ok {
+			scores[id] += score
+			delete(scores, old)
+		}
+	}
+}
+
+// Ranker scores entries by use
+type Ranker interface {
+	Frecency(id string, now time.Time) float64
+}
+
+// Ranking adds scores from other launchers to our own history
+type Ranking struct {
+	History  History
+	External Scores
+}
+
+func (r Ranking) Frecency(id string, now time.Time) float64 {
+	return r.History.Frecency(id, now) + r.External[id]
+}
@@ -0,0 +1,43 @@
+package entries
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestUsage(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "usage", "launches.tsv")
+	now := time.Unix(1700000000, 0)
+	for _, id := range []string{"foot.desktop", "firefox.desktop"} {
+		if err := AppendUsage(path, id, now); err != nil {
+			t.Fatal(err)
+		}
+	}
+	contents, _ := ioutil.ReadFile(path)
+	if want := "1700000000\tfoot.desktop\n1700000000\tfirefox.desktop\n"; string(contents) != want {
+		t.Errorf("got %q, expected %q", contents, want)
+	}
+
+	scoresPath := filepath.Join(t.TempDir(), "scores")
+	ioutil.WriteFile(scoresPath, []byte("12.5 firefox.desktop\n  3 foot.desktop\nbroken line here\n-1 bad.desktop\n"), 0644)
+	scores, err := LoadScores(scoresPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(scores) != 2 || scores["firefox.desktop"] != 12.5 || scores["foot.desktop"] != 3 {
+		t.Errorf("got %v", scores)
+	}
+
+	history := History{}
+	history.Record("foot.desktop", now)
+	ranking := Ranking{History: history, External: scores}
+	if got := ranking.Frecency("foot.desktop", now); got != 4 {
+		t.Errorf("expected our frecency plus the external score, got %v", got)
+	}
+	top := Top(ranking, []DesktopEntry{{DesktopID: "foot.desktop"}, {DesktopID: "firefox.desktop"}, {DesktopID: "never.desktop"}}, 5, now)
+	if len(top) != 2 || top[0].DesktopID != "firefox.desktop" {
+		t.Errorf("got %v", top)
+	}
+}
@@ -0,0 +1,61 @@
+package entries
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+)
+
+// Validate returns why the entry can't be launched from the grid, nil if it
+// can. Links open their URL.
+func Validate(entry DesktopEntry) error {
+	switch {
+	case entry.Type != "Application" && entry.Type != "Link":
+		// directories, menu folders, have nothing to run
+		return fmt.Errorf("type %q is neither Application nor Link", entry.Type)
+	case entry.Name == "":
+		return errors.New("no Name")
+	case entry.Type == "Link" && entry.URL == "":
+		return errors.New("no URL")
+	case entry.Exec == "":
+		return errors.New("no Exec")
+	}
+	return nil
+}
+
+// Valid returns the valid entries of the list, and why the others were
+// dropped: desktop ID -> reason
+func Valid(list []DesktopEntry) ([]DesktopEntry, map[string]error) {
+	valid := make([]DesktopEntry, 0, len(list))
+	dropped := make(map[string]error)
+	for _, entry := range list {
+		if err := Validate(entry); err != nil {
+			dropped[entry.DesktopID] = err
+			continue
+		}
+		valid = append(valid, entry)
+	}
+	return valid, dropped
+}
+
+// Installed tells whether the program named by TryExec, if any, is there: an
+// executable at an absolute path or in PATH
+func (entry DesktopEntry) Installed() bool {
+	if entry.TryExec == "" {
+		return true
+	}
+	_, err := exec.LookPath(entry.TryExec)
+	return err == nil
+}
+
+// InstalledOnly returns the entries of the list with their TryExec program
+// installed, and the number of the others
+func InstalledOnly(list []DesktopEntry) ([]DesktopEntry, int) {
+	installed := make([]DesktopEntry, 0, len(list))
+	for _, entry := range list {
+		if entry.Installed() {
+			installed = append(installed, entry)
+		}
+	}
+	return installed, len(list) - len(installed)
+}
@@ -0,0 +1,54 @@
+package entries
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func TestValid(t *testing.T) {
+	list := []DesktopEntry{
+		{DesktopID: "app.desktop", Type: "Application", Name: "App", Exec: "app"},
+		{DesktopID: "link.desktop", Type: "Link", Name: "Link", URL: "https://example.com", Exec: linkExec("https://example.com")},
+		{DesktopID: "nourl.desktop", Type: "Link", Name: "No URL"},
+		{DesktopID: "dir.directory", Type: "Directory", Name: "Dir"},
+		{DesktopID: "untyped.desktop", Name: "Untyped", Exec: "untyped"},
+		{DesktopID: "nameless.desktop", Type: "Application", Exec: "nameless"},
+		{DesktopID: "noexec.desktop", Type: "Application", Name: "No Exec"},
+	}
+
+	valid, dropped := Valid(list)
+	if len(valid) != 2 || valid[0].DesktopID != "app.desktop" || valid[1].DesktopID != "link.desktop" {
+		t.Errorf("expected app.desktop and link.desktop, got %v", valid)
+	}
+	for id, want := range map[string]string{
+		"nourl.desktop":    "no URL",
+		"dir.directory":    `type "Directory" is neither Application nor Link`,
+		"untyped.desktop":  `type "" is neither Application nor Link`,
+		"nameless.desktop": "no Name",
+		"noexec.desktop":   "no Exec",
+	} {
+		if err := dropped[id]; err == nil || err.Error() != want {
+			t.Errorf("%s: expected %q, got %v", id, want, err)
+		}
+	}
+}
+
+func TestInstalledOnly(t *testing.T) {
+	dir := t.TempDir()
+	ioutil.WriteFile(filepath.Join(dir, "present"), []byte("#!/bin/sh\n"), 0755)
+	ioutil.WriteFile(filepath.Join(dir, "not-executable"), nil, 0644)
+	t.Setenv("PATH", dir)
+
+	list := []DesktopEntry{
+		{DesktopID: "plain.desktop"},
+		{DesktopID: "present.desktop", TryExec: "present"},
+		{DesktopID: "absolute.desktop", TryExec: filepath.Join(dir, "present")},
+		{DesktopID: "removed.desktop", TryExec: "removed"},
+		{DesktopID: "stub.desktop", TryExec: filepath.Join(dir, "not-executable")},
+	}
+	installed, missing := InstalledOnly(list)
+	if len(installed) != 3 || missing != 2 {
+		t.Errorf("expected the first 3 entries, got %v", installed)
+	}
+}
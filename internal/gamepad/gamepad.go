@@ -0,0 +1,215 @@
+// Package gamepad reads game controllers through evdev. The user needs read
+// access to /dev/input, usually by being in the "input" group.
+package gamepad
+
+import (
+	"encoding/binary"
+	"log"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+// Event is a navigation action triggered on a controller
+type Event int
+
+const (
+	Up Event = iota
+	Down
+	Left
+	Right
+	Activate
+	Back
+)
+
+// udev links every controller here
+const devicesGlob = "/dev/input/by-id/*-event-joystick"
+
+const rescanInterval = 3 * time.Second
+
+// Event types and codes from linux/input-event-codes.h
+const (
+	evKey = 0x01
+	evAbs = 0x03
+
+	absX     = 0x00
+	absY     = 0x01
+	absHat0X = 0x10
+	absHat0Y = 0x11
+
+	btnSouth     = 0x130 // A, cross
+	btnEast      = 0x131 // B, circle
+	btnStart     = 0x13b
+	btnDpadUp    = 0x220
+	btnDpadDown  = 0x221
+	btnDpadLeft  = 0x222
+	btnDpadRight = 0x223
+)
+
+// struct input_event
+type inputEvent struct {
+	Time  syscall.Timeval
+	Type  uint16
+	Code  uint16
+	Value int32
+}
+
+// struct input_absinfo
+type absInfo struct {
+	Value, Minimum, Maximum, Fuzz, Flat, Resolution int32
+}
+
+// Per-device state turning raw events into navigation events
+type device struct {
+	// stick axis -> range reported by the device
+	ranges map[uint16]absInfo
+	// stick axis -> direction it was last pushed to (-1, 0, 1)
+	pushed map[uint16]int
+}
+
+func newDevice() *device {
+	return &device{
+		ranges: make(map[uint16]absInfo),
+		pushed: make(map[uint16]int),
+	}
+}
+
+// Returns the navigation event for a raw event, if any
+func (d *device) handle(ev inputEvent) (Event, bool) {
+	switch ev.Type {
+	case evKey:
+		if ev.Value != 1 {
+			// only presses, not releases and autorepeat
+			return 0, false
+		}
+		switch ev.Code {
+		case btnSouth, btnStart:
+			return Activate, true
+		case btnEast:
+			return Back, true
+		case btnDpadUp:
+			return Up, true
+		case btnDpadDown:
+			return Down, true
+		case btnDpadLeft:
+			return Left, true
+		case btnDpadRight:
+			return Right, true
+		}
+	case evAbs:
+		var dir int
+		switch ev.Code {
+		case absHat0X, absHat0Y:
+			dir = sign(int(ev.Value))
+		case absX, absY:
+			// half way between the center and the edge counts as pushed
+			r, ok := d.ranges[ev.Code]
+			if !ok || r.Maximum <= r.Minimum {
+				return 0, false
+			}
+			center := (int(r.Minimum) + int(r.Maximum)) / 2
+			threshold := (int(r.Maximum) - int(r.Minimum)) / 4
+			if offset := int(ev.Value) - center; offset > threshold || offset < -threshold {
+				dir = sign(offset)
+			}
+		default:
+			return 0, false
+		}
+
+		// only report the moment the axis gets pushed
+		if dir == d.pushed[ev.Code] {
+			return 0, false
+		}
+		d.pushed[ev.Code] = dir
+		horizontal := ev.Code == absX || ev.Code == absHat0X
+		switch {
+		case dir < 0 && horizontal:
+			return Left, true
+		case dir > 0 && horizontal:
+			return Right, true
+		case dir < 0:
+			return Up, true
+		case dir > 0:
+			return Down, true
+		}
+	}
+	return 0, false
+}
+
+func sign(i int) int {
+	switch {
+	case i < 0:
+		return -1
+	case i > 0:
+		return 1
+	}
+	return 0
+}
+
+// Watch reads all controllers, present and plugged in later, and calls handle
+// (from other goroutines) for every navigation event
+func Watch(handle func(Event)) {
+	go func() {
+		open := make(map[string]bool)
+		closed := make(chan string)
+		for {
+			paths, _ := filepath.Glob(devicesGlob)
+			for _, path := range paths {
+				if open[path] {
+					continue
+				}
+				f, err := os.Open(path)
+				if err != nil {
+					log.Printf("Couldn't open controller: %s", err)
+					continue
+				}
+				log.Printf("Controller found: %s\n", filepath.Base(path))
+				open[path] = true
+				go func(path string) {
+					read(f, handle)
+					f.Close()
+					closed <- path
+				}(path)
+			}
+
+			select {
+			case path := <-closed:
+				delete(open, path)
+			case <-time.After(rescanInterval):
+			}
+		}
+	}()
+}
+
+// Reads events until the device goes away
+func read(f *os.File, handle func(Event)) {
+	d := newDevice()
+	for _, axis := range []uint16{absX, absY} {
+		if r, err := getAbsInfo(f, axis); err == nil {
+			d.ranges[axis] = r
+		}
+	}
+
+	for {
+		var ev inputEvent
+		if err := binary.Read(f, binary.LittleEndian, &ev); err != nil {
+			return
+		}
+		if e, ok := d.handle(ev); ok {
+			handle(e)
+		}
+	}
+}
+
+// EVIOCGABS ioctl
+func getAbsInfo(f *os.File, axis uint16) (absInfo, error) {
+	var info absInfo
+	request := uintptr(2<<30 | unsafe.Sizeof(info)<<16 | 'E'<<8 | (0x40 + uintptr(axis)))
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, f.Fd(), request, uintptr(unsafe.Pointer(&info)))
+	if errno != 0 {
+		return info, errno
+	}
+	return info, nil
+}
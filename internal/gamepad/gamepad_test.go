@@ -0,0 +1,36 @@
+package gamepad
+
+import "testing"
+
+func TestDeviceHandle(t *testing.T) {
+	d := newDevice()
+	d.ranges[absX] = absInfo{Minimum: 0, Maximum: 255}
+
+	var got []Event
+	for _, ev := range []inputEvent{
+		{Type: evKey, Code: btnSouth, Value: 1},
+		{Type: evKey, Code: btnSouth, Value: 0},
+		{Type: evAbs, Code: absHat0Y, Value: -1},
+		{Type: evAbs, Code: absHat0Y, Value: 0},
+		{Type: evAbs, Code: absX, Value: 140}, // inside the dead zone
+		{Type: evAbs, Code: absX, Value: 250},
+		{Type: evAbs, Code: absX, Value: 255}, // still pushed, no repeat
+		{Type: evAbs, Code: absX, Value: 128},
+		{Type: evAbs, Code: absX, Value: 3},
+		{Type: evKey, Code: btnEast, Value: 1},
+	} {
+		if e, ok := d.handle(ev); ok {
+			got = append(got, e)
+		}
+	}
+
+	want := []Event{Activate, Up, Right, Left, Back}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("event %d: expected %v, got %v", i, want[i], got[i])
+		}
+	}
+}
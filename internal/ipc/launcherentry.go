@@ -0,0 +1,110 @@
+package ipc
+
+import (
+	"bufio"
+	"io"
+	"log"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/ftphikari/wlaunchpad/internal/config"
+)
+
+func init() {
+	config.AddFeature("ipc", "launcher-entry")
+}
+
+// LauncherEntryState is the state reported by an application over
+// com.canonical.Unity.LauncherEntry
+type LauncherEntryState struct {
+	Progress        float64
+	ProgressVisible bool
+	Count           int
+	CountVisible    bool
+}
+
+// Update merges properties of an Update signal into the state. Applications
+// only send the properties that changed.
+func (state *LauncherEntryState) Update(props map[string]string) {
+	for k, v := range props {
+		switch k {
+		case "progress":
+			state.Progress, _ = strconv.ParseFloat(v, 64)
+		case "progress-visible":
+			state.ProgressVisible, _ = strconv.ParseBool(v)
+		case "count":
+			state.Count, _ = strconv.Atoi(v)
+		case "count-visible":
+			state.CountVisible, _ = strconv.ParseBool(v)
+		}
+	}
+}
+
+// WatchLauncherEntries listens for LauncherEntry Update signals on the session
+// bus and calls update (from another goroutine) for each of them. There is no
+// D-Bus binding in gotk3, so we let dbus-monitor do the heavy lifting and parse
+// its output.
+func WatchLauncherEntries(update func(id string, props map[string]string)) error {
+	cmd := exec.Command("dbus-monitor", "--session",
+		"type='signal',interface='com.canonical.Unity.LauncherEntry',member='Update'")
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	go func() {
+		ParseLauncherEntryUpdates(stdout, update)
+		cmd.Wait()
+		log.Println("dbus-monitor exited, LauncherEntry updates stopped")
+	}()
+	return nil
+}
+
+// ParseLauncherEntryUpdates parses dbus-monitor output and calls update with
+// the DesktopID and the properties of every Update signal. A signal looks like
+// this:
+//
+//	signal time=... interface=com.canonical.Unity.LauncherEntry; member=Update
+//	   string "application://firefox.desktop"
+//	   array [
+//	      dict entry(
+//	         string "progress"
+//	         variant             double 0.42
+//	      )
+//	   ]
+func ParseLauncherEntryUpdates(in io.Reader, update func(id string, props map[string]string)) {
+	var appURI, key string
+	props := make(map[string]string)
+
+	scanner := bufio.NewScanner(in)
+	for scanner.Scan() {
+		l := strings.TrimSpace(scanner.Text())
+		switch {
+		case strings.HasPrefix(l, "signal "):
+			appURI, key = "", ""
+			props = make(map[string]string)
+		case strings.HasPrefix(l, "string "):
+			value, _ := strconv.Unquote(strings.TrimPrefix(l, "string "))
+			if appURI == "" {
+				appURI = value
+			} else {
+				key = value
+			}
+		case strings.HasPrefix(l, "variant "):
+			fields := strings.Fields(l)
+			if key != "" && len(fields) == 3 {
+				props[key] = fields[2]
+			}
+			key = ""
+		case l == "]":
+			if strings.HasPrefix(appURI, "application://") {
+				update(strings.TrimPrefix(appURI, "application://"), props)
+			}
+			appURI = ""
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package ipc
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseLauncherEntryUpdates(t *testing.T) {
+	const output = `signal time=1634300000.1 sender=:1.42 -> destination=(null destination) serial=7 path=/com/canonical/unity/launcherentry/1; interface=com.canonical.Unity.LauncherEntry; member=Update
+   string "application://firefox.desktop"
+   array [
+      dict entry(
+         string "progress"
+         variant             double 0.42
+      )
+      dict entry(
+         string "progress-visible"
+         variant             boolean true
+      )
+   ]
+signal time=1634300001.1 sender=:1.43 -> destination=(null destination) serial=8 path=/com/canonical/unity/launcherentry/2; interface=com.canonical.Unity.LauncherEntry; member=Update
+   string "application://steam.desktop"
+   array [
+      dict entry(
+         string "count"
+         variant             int64 3
+      )
+   ]
+`
+
+	updates := make(map[string]map[string]string)
+	ParseLauncherEntryUpdates(strings.NewReader(output), func(id string, props map[string]string) {
+		updates[id] = props
+	})
+
+	if len(updates) != 2 {
+		t.Fatalf("expected 2 updates, got %d", len(updates))
+	}
+
+	if updates["firefox.desktop"]["progress"] != "0.42" || updates["firefox.desktop"]["progress-visible"] != "true" {
+		t.Errorf("failed to parse firefox progress: %v", updates["firefox.desktop"])
+	}
+
+	if updates["steam.desktop"]["count"] != "3" {
+		t.Errorf("failed to parse steam count: %v", updates["steam.desktop"])
+	}
+}
+
+func TestLauncherEntryStateUpdate(t *testing.T) {
+	var state LauncherEntryState
+	state.Update(map[string]string{"progress": "0.5", "progress-visible": "true"})
+	state.Update(map[string]string{"count": "2"})
+
+	if state.Progress != 0.5 || !state.ProgressVisible || state.Count != 2 || state.CountVisible {
+		t.Errorf("failed to merge updates, got %+v", state)
+	}
+}
@@ -0,0 +1,86 @@
+// Package ipc talks to other processes: running wlaunchpad instances and
+// desktop services on the session bus.
+package ipc
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// LockFilePid returns the PID of the process locking filename
+func LockFilePid(filename string) (pid int, err error) {
+	contents, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return
+	}
+
+	pid, err = strconv.Atoi(string(contents))
+	return
+}
+
+// CreateLockFile tries to create a file with given name and acquire an
+// exclusive lock on it. If the file already exists AND is still locked, it will
+// fail.
+func CreateLockFile(filename string) (*os.File, error) {
+	file, err := os.OpenFile(filename, os.O_WRONLY|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	err = syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	// Write PID to lock file
+	contents := strconv.Itoa(os.Getpid())
+	if err := file.Truncate(0); err != nil {
+		file.Close()
+		return nil, err
+	}
+	if _, err := file.WriteString(contents); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return file, nil
+}
+
+// TempDir returns the directory for the lock file
+func TempDir() string {
+	if os.Getenv("TMPDIR") != "" {
+		return os.Getenv("TMPDIR")
+	} else if os.Getenv("TEMP") != "" {
+		return os.Getenv("TEMP")
+	} else if os.Getenv("TMP") != "" {
+		return os.Getenv("TMP")
+	} else if os.Getenv("XDG_RUNTIME_DIR") != "" {
+		return os.Getenv("XDG_RUNTIME_DIR")
+	}
+	return "/tmp"
+}
+
+// InstanceName returns the base name of the lock file and socket. It includes
+// the user and the Wayland display, so every session (a nested compositor
+// too) gets its own instance: "wlaunchpad-1000-wayland-1".
+func InstanceName() string {
+	display := os.Getenv("WAYLAND_DISPLAY")
+	if display == "" {
+		display = "wayland-0"
+	}
+	// WAYLAND_DISPLAY may also be an absolute path to the socket
+	display = strings.TrimPrefix(display, "/")
+	display = strings.ReplaceAll(display, "/", "-")
+	return fmt.Sprintf("wlaunchpad-%d-%s", os.Getuid(), display)
+}
+
+// LockFilePath returns the path of the lock file held by a running instance
+func LockFilePath() string {
+	return filepath.Join(TempDir(), InstanceName()+".lock")
+}
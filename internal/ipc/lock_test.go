@@ -0,0 +1,41 @@
+package ipc
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLockFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "wlaunchpad.lock")
+	lockFile, err := CreateLockFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lockFile.Close()
+
+	if _, err := CreateLockFile(path); err == nil {
+		t.Error("locked the same file twice")
+	}
+
+	pid, err := LockFilePid(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pid != os.Getpid() {
+		t.Errorf("expected PID %d, got %d", os.Getpid(), pid)
+	}
+}
+
+func TestInstanceName(t *testing.T) {
+	t.Setenv("WAYLAND_DISPLAY", "wayland-1")
+	first := InstanceName()
+	t.Setenv("WAYLAND_DISPLAY", "/run/user/1000/wayland-2")
+	second := InstanceName()
+	if first == second {
+		t.Errorf("same name %q for different displays", first)
+	}
+	if filepath.Base(second) != second {
+		t.Errorf("name %q contains a path separator", second)
+	}
+}
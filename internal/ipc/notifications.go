@@ -0,0 +1,85 @@
+package ipc
+
+import (
+	"encoding/json"
+	"log"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/ftphikari/wlaunchpad/internal/config"
+)
+
+func init() {
+	config.AddFeature("ipc", "notifications")
+}
+
+// makoctl lists the notifications on screen as a D-Bus "aa{sv}" value
+// serialized to JSON: {"type": "aa{sv}", "data": [[{"key": {"type": "s", "data": ...}}]]}
+type notificationList struct {
+	Data [][]map[string]struct {
+		Data interface{} `json:"data"`
+	} `json:"data"`
+}
+
+// Key of NotificationCounts for the notifications of apps not told apart
+const AllApps = "*"
+
+// NotificationCounts returns map application key -> number of notifications
+// mako currently shows. Every notification is counted under its desktop-entry
+// hint, or under its lowercased application name if the sender gave none.
+// dunst only tells how many notifications it shows or holds back, not which
+// apps sent them, so with dunst they are all counted under AllApps.
+// Notifications already dismissed aren't counted.
+func NotificationCounts() map[string]int {
+	if out, err := exec.Command("makoctl", "list").Output(); err == nil {
+		counts, err := parseNotificationList(out)
+		if err != nil {
+			log.Printf("Couldn't parse makoctl output: %s", err)
+		}
+		return counts
+	}
+
+	counts := make(map[string]int)
+	for _, state := range []string{"displayed", "waiting"} {
+		out, err := exec.Command("dunstctl", "count", state).Output()
+		if err != nil {
+			break
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(string(out)))
+		if err != nil {
+			log.Printf("Couldn't parse dunstctl output: %s", err)
+			break
+		}
+		counts[AllApps] += n
+	}
+	return counts
+}
+
+func parseNotificationList(out []byte) (map[string]int, error) {
+	counts := make(map[string]int)
+	var list notificationList
+	if err := json.Unmarshal(out, &list); err != nil {
+		return counts, err
+	}
+
+	for _, group := range list.Data {
+		for _, n := range group {
+			if hint, ok := n["desktop-entry"].Data.(string); ok && hint != "" {
+				counts[strings.TrimSuffix(hint, ".desktop")]++
+			} else if name, ok := n["app-name"].Data.(string); ok && name != "" {
+				counts[strings.ToLower(name)]++
+			}
+		}
+	}
+	return counts, nil
+}
+
+// NotificationCount returns the number of notifications for a desktop entry,
+// preferring the desktop-entry hint over the application name
+func NotificationCount(counts map[string]int, desktopID, name string) int {
+	if n, ok := counts[strings.TrimSuffix(desktopID, ".desktop")]; ok {
+		return n
+	}
+	return counts[strings.ToLower(name)]
+}
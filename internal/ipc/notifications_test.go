@@ -0,0 +1,29 @@
+package ipc
+
+import "testing"
+
+func TestParseNotificationList(t *testing.T) {
+	const output = `{"type": "aa{sv}", "data": [[
+	{"app-name": {"type": "s", "data": "Firefox"}, "desktop-entry": {"type": "s", "data": "firefox"}, "id": {"type": "u", "data": 3}},
+	{"app-name": {"type": "s", "data": "Firefox"}, "desktop-entry": {"type": "s", "data": "firefox.desktop"}, "id": {"type": "u", "data": 2}},
+	{"app-name": {"type": "s", "data": "notify-send"}, "id": {"type": "u", "data": 1}}
+]]}`
+
+	counts, err := parseNotificationList([]byte(output))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := NotificationCount(counts, "firefox.desktop", "Firefox"); n != 2 {
+		t.Errorf("firefox: got %d, want 2", n)
+	}
+	if n := NotificationCount(counts, "org.example.Tool.desktop", "notify-send"); n != 1 {
+		t.Errorf("notify-send: got %d, want 1", n)
+	}
+	if n := NotificationCount(counts, "gimp.desktop", "GIMP"); n != 0 {
+		t.Errorf("gimp: got %d, want 0", n)
+	}
+
+	if _, err := parseNotificationList([]byte("not json")); err == nil {
+		t.Error("expected an error for invalid output")
+	}
+}
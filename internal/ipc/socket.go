@@ -0,0 +1,205 @@
+package ipc
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/ftphikari/wlaunchpad/internal/config"
+)
+
+func init() {
+	config.AddFeature("ipc", "socket")
+}
+
+// Request is a command for a running instance. On the wire and in
+// wlaunchpad:// URLs it has the form of a URL path and query: "show?q=firefox".
+type Request struct {
+	Action string
+	Args   url.Values
+}
+
+// Actions a request may ask for -> arguments allowed with them. Requests also
+// come from web pages through the URL scheme, so anything else is rejected.
+var actions = map[string][]string{
+	"show":   {"q", "category"},
+	"hide":   nil,
+	"toggle": nil,
+	// appearance flags given to another invocation: columns, icon size, spacing
+	"configure": {"c", "i", "s"},
+	// applies the theme and -style file again, for previews while editing
+	"style": nil,
+	// answered with Warnings, for status bars
+	"warnings": nil,
+}
+
+// Actions allowed in wlaunchpad:// URLs
+var urlActions = []string{"show", "hide", "toggle"}
+
+const maxArgLength = 256
+
+// ParseRequest parses and validates a request in "action?arg=value" form
+func ParseRequest(s string) (Request, error) {
+	parts := strings.SplitN(s, "?", 2)
+	req := Request{Action: parts[0], Args: url.Values{}}
+	allowed, ok := actions[req.Action]
+	if !ok {
+		return req, fmt.Errorf("unknown action %q", req.Action)
+	}
+
+	if len(parts) == 2 {
+		var err error
+		req.Args, err = url.ParseQuery(parts[1])
+		if err != nil {
+			return req, err
+		}
+	}
+	for name, values := range req.Args {
+		if !contains(allowed, name) {
+			return req, fmt.Errorf("argument %q not allowed for %s", name, req.Action)
+		}
+		for _, v := range values {
+			if len(v) > maxArgLength || strings.IndexFunc(v, unicode.IsControl) != -1 {
+				return req, fmt.Errorf("invalid value for argument %q", name)
+			}
+		}
+	}
+	return req, nil
+}
+
+// ParseURL parses and validates a wlaunchpad://action?arg=value URL
+func ParseURL(raw string) (Request, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return Request{}, err
+	}
+	if u.Scheme != "wlaunchpad" {
+		return Request{}, fmt.Errorf("not a wlaunchpad URL: %s", raw)
+	}
+
+	// both wlaunchpad://show?q=x and wlaunchpad:show?q=x
+	action := u.Host
+	if action == "" {
+		action = u.Opaque
+	}
+	action += strings.TrimSuffix(u.Path, "/")
+	if !contains(urlActions, action) {
+		return Request{}, fmt.Errorf("action %q not allowed in URLs", action)
+	}
+	return ParseRequest(action + "?" + u.RawQuery)
+}
+
+func (r Request) String() string {
+	if len(r.Args) == 0 {
+		return r.Action
+	}
+	return r.Action + "?" + r.Args.Encode()
+}
+
+// SocketPath returns the path of the socket a running instance listens on
+func SocketPath() string {
+	return filepath.Join(TempDir(), InstanceName()+".sock")
+}
+
+// Serve listens for requests on a unix socket at path and calls handle (from
+// another goroutine) for every valid one. What handle returns, if anything, is
+// sent back as a line before "ok", see Query. Whoever holds the lock file owns
+// the socket, so a stale one is removed.
+func Serve(path string, handle func(Request) string) error {
+	os.Remove(path)
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return err
+	}
+	if err := os.Chmod(path, 0600); err != nil {
+		listener.Close()
+		return err
+	}
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				log.Printf("Stopped listening on %s: %s", path, err)
+				return
+			}
+			go serveConn(conn, handle)
+		}
+	}()
+	return nil
+}
+
+func serveConn(conn net.Conn, handle func(Request) string) {
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(time.Second))
+
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil {
+		return
+	}
+	req, err := ParseRequest(strings.TrimSpace(line))
+	if err != nil {
+		log.Printf("Rejected request %q: %s", line, err)
+		fmt.Fprintf(conn, "error: %s\n", err)
+		return
+	}
+	log.Printf("Request received: %s\n", req)
+	if reply := handle(req); reply != "" {
+		fmt.Fprintln(conn, reply)
+	}
+	fmt.Fprintln(conn, "ok")
+}
+
+// Send sends a request to the instance listening at path
+func Send(path string, req Request) error {
+	_, err := Query(path, req)
+	return err
+}
+
+// Query sends the request like Send, and returns the line answering it, ""
+// for requests answered with just "ok"
+func Query(path string, req Request) (string, error) {
+	conn, err := net.DialTimeout("unix", path, time.Second)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(time.Second))
+
+	if _, err := fmt.Fprintln(conn, req); err != nil {
+		return "", err
+	}
+	r := bufio.NewReader(conn)
+	answer := ""
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return "", err
+		}
+		switch line = strings.TrimSpace(line); {
+		case line == "ok":
+			return answer, nil
+		case strings.HasPrefix(line, "error: "):
+			return "", errors.New(strings.TrimPrefix(line, "error: "))
+		default:
+			answer = line
+		}
+	}
+}
+
+func contains(slice []string, val string) bool {
+	for _, item := range slice {
+		if item == val {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,63 @@
+package ipc
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestParseURL(t *testing.T) {
+	for _, raw := range []string{"wlaunchpad://show?q=fire+fox", "wlaunchpad:show?q=fire%20fox"} {
+		req, err := ParseURL(raw)
+		if err != nil {
+			t.Fatalf("%s: %s", raw, err)
+		}
+		if req.Action != "show" || req.Args.Get("q") != "fire fox" {
+			t.Errorf("%s: got %+v", raw, req)
+		}
+	}
+
+	for _, raw := range []string{
+		"https://show?q=x",
+		"wlaunchpad://launch?id=firefox.desktop",
+		"wlaunchpad://show?exec=rm",
+		"wlaunchpad://hide?q=x",
+		"wlaunchpad://configure?c=1",
+		"wlaunchpad://show?q=a%0Ab",
+	} {
+		if _, err := ParseURL(raw); err == nil {
+			t.Errorf("%s: expected an error", raw)
+		}
+	}
+}
+
+func TestSendRequest(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "wlaunchpad.sock")
+	received := make(chan Request, 1)
+	if err := Serve(path, func(req Request) string {
+		if req.Action == "warnings" {
+			return NewWarnings([]Invalid{{ID: "blank.desktop", Path: "/usr/share/applications/blank.desktop", Reason: "no Name"}}, nil).String()
+		}
+		received <- req
+		return ""
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	req, _ := ParseRequest("show?q=term")
+	if err := Send(path, req); err != nil {
+		t.Fatal(err)
+	}
+	if got := <-received; got.Action != "show" || got.Args.Get("q") != "term" {
+		t.Errorf("got %+v", got)
+	}
+
+	if err := Send(path, Request{Action: "rm"}); err == nil {
+		t.Error("expected an error for a request not on the allowlist")
+	}
+
+	answer, err := Query(path, Request{Action: "warnings"})
+	want := `{"count":1,"invalid":[{"id":"blank.desktop","path":"/usr/share/applications/blank.desktop","reason":"no Name"}],"slow":[]}`
+	if err != nil || answer != want {
+		t.Errorf("got %s, %v, expected %s", answer, err, want)
+	}
+}
@@ -0,0 +1,38 @@
+package ipc
+
+import "encoding/json"
+
+// Warnings answers the "warnings" request: what the last scan of the desktop
+// files had to skip, as JSON
+type Warnings struct {
+	// len(Invalid) + len(Slow)
+	Count   int       `json:"count"`
+	Invalid []Invalid `json:"invalid"`
+	// application directories not read in time
+	Slow []string `json:"slow"`
+}
+
+// Invalid is a desktop file skipped as it can't be launched
+type Invalid struct {
+	ID     string `json:"id"`
+	Path   string `json:"path"`
+	Reason string `json:"reason"`
+}
+
+// NewWarnings returns the warnings about the invalid files and slow
+// directories, with the count filled in
+func NewWarnings(invalid []Invalid, slow []string) Warnings {
+	// empty lists rather than null, for scripts
+	if invalid == nil {
+		invalid = []Invalid{}
+	}
+	if slow == nil {
+		slow = []string{}
+	}
+	return Warnings{Count: len(invalid) + len(slow), Invalid: invalid, Slow: slow}
+}
+
+func (w Warnings) String() string {
+	b, _ := json.Marshal(w)
+	return string(b)
+}
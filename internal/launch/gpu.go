@@ -0,0 +1,60 @@
+package launch
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Environment variables making apps render on the other GPU: Mesa's
+// DRI_PRIME, and for the NVIDIA driver its render offload ones
+var (
+	mesaOffload   = []string{"DRI_PRIME=1"}
+	nvidiaOffload = []string{"__NV_PRIME_RENDER_OFFLOAD=1", "__GLX_VENDOR_LIBRARY_NAME=nvidia", "__VK_LAYER_NV_optimus=NVIDIA_only"}
+)
+
+// GPUs describes the graphics cards of the machine
+type GPUs struct {
+	Count int
+	// NVIDIA is whether one of them runs NVIDIA's own driver
+	NVIDIA bool
+}
+
+// ProbeGPUs looks the graphics cards up in sysDir, /sys normally
+func ProbeGPUs(sysDir string) GPUs {
+	var gpus GPUs
+	cards, _ := filepath.Glob(filepath.Join(sysDir, "class", "drm", "card[0-9]*"))
+	for _, card := range cards {
+		// card0-eDP-1 and the like are connectors
+		if strings.Contains(filepath.Base(card), "-") {
+			continue
+		}
+		gpus.Count++
+		if driver, err := os.Readlink(filepath.Join(card, "device", "driver")); err == nil && filepath.Base(driver) == "nvidia" {
+			gpus.NVIDIA = true
+		}
+	}
+	return gpus
+}
+
+// Dual reports whether there's another GPU than the default one
+func (g GPUs) Dual() bool {
+	return g.Count > 1
+}
+
+// UseNonDefaultGPU makes the command render on the other GPU, as asked for by
+// PrefersNonDefaultGPU=true. Machines with one GPU have nothing to switch to.
+func UseNonDefaultGPU(cmd *exec.Cmd, gpus GPUs) {
+	if !gpus.Dual() {
+		return
+	}
+	if cmd.Env == nil {
+		cmd.Env = os.Environ()
+	}
+	if gpus.NVIDIA {
+		cmd.Env = append(cmd.Env, nvidiaOffload...)
+	} else {
+		cmd.Env = append(cmd.Env, mesaOffload...)
+	}
+}
@@ -0,0 +1,57 @@
+package launch
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// Makes a sysfs tree with a card for each driver
+func fakeSysfs(t *testing.T, drivers ...string) string {
+	sys := t.TempDir()
+	for i, driver := range drivers {
+		card := filepath.Join(sys, "class", "drm", "card"+string(rune('0'+i)))
+		if err := os.MkdirAll(filepath.Join(card, "device"), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Symlink(filepath.Join(sys, "bus", "pci", "drivers", driver), filepath.Join(card, "device", "driver")); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.MkdirAll(card+"-eDP-1", 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return sys
+}
+
+func TestProbeGPUs(t *testing.T) {
+	if gpus := ProbeGPUs(fakeSysfs(t, "i915")); gpus != (GPUs{Count: 1}) {
+		t.Errorf("got %+v", gpus)
+	}
+	if gpus := ProbeGPUs(fakeSysfs(t, "i915", "nvidia")); gpus != (GPUs{Count: 2, NVIDIA: true}) {
+		t.Errorf("got %+v", gpus)
+	}
+	if gpus := ProbeGPUs(t.TempDir()); gpus.Dual() {
+		t.Errorf("got %+v", gpus)
+	}
+}
+
+func TestUseNonDefaultGPU(t *testing.T) {
+	cmd := Command("game", Entry{}, false, "foot")
+	UseNonDefaultGPU(cmd, GPUs{Count: 1})
+	if cmd.Env != nil {
+		t.Errorf("nothing to switch to with one GPU, got %q", cmd.Env)
+	}
+
+	UseNonDefaultGPU(cmd, GPUs{Count: 2})
+	if !reflect.DeepEqual(cmd.Env[len(cmd.Env)-1:], []string{"DRI_PRIME=1"}) {
+		t.Errorf("expected DRI_PRIME, got %q", cmd.Env[len(cmd.Env)-1:])
+	}
+
+	cmd = Command("game", Entry{}, false, "foot")
+	UseNonDefaultGPU(cmd, GPUs{Count: 2, NVIDIA: true})
+	if !reflect.DeepEqual(cmd.Env[len(cmd.Env)-3:], nvidiaOffload) {
+		t.Errorf("expected the NVIDIA offload variables, got %q", cmd.Env[len(cmd.Env)-3:])
+	}
+}
@@ -0,0 +1,186 @@
+// Package launch starts applications from desktop entry Exec lines.
+package launch
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+)
+
+// Entry holds what the field codes of an Exec line expand to
+type Entry struct {
+	// Name is the localized name, for %c
+	Name string
+	// Icon is the icon name or path, for %i
+	Icon string
+	// Path is the location of the desktop file, for %k
+	Path string
+	// Dir is the working directory, the launcher's own if empty
+	Dir string
+	// Files are the files or URLs to open, for %f, %F, %u and %U
+	Files []string
+}
+
+// Command returns the command to run for an Exec line of entry. Terminal
+// applications are started in the term terminal emulator, see
+// TerminalCommand.
+func Command(command string, entry Entry, terminal bool, term string) *exec.Cmd {
+	elements, err := Split(command)
+	if err != nil {
+		log.Print(err)
+		elements = strings.Fields(command)
+	}
+	elements = expandFieldCodes(elements, entry)
+
+	// find prepended env variables, if any, also behind env like in
+	// "env BAMF_DESKTOP_FILE_HINT=... /snap/bin/firefox" of snaps
+	if len(elements) > 2 && elements[0] == "env" && isEnvVar(elements[1]) {
+		elements = elements[1:]
+	}
+	var envVars []string
+	for len(elements) > 1 && isEnvVar(elements[0]) {
+		envVars = append(envVars, elements[0])
+		elements = elements[1:]
+	}
+	if len(elements) == 0 {
+		elements = []string{""}
+	}
+
+	cmd := exec.Command(elements[0], elements[1:]...)
+
+	if terminal {
+		args := append(TerminalCommand(term), elements...)
+		cmd = exec.Command(args[0], args[1:]...)
+	}
+
+	Detach(cmd)
+	cmd.Dir = entry.Dir
+
+	// set env variables
+	if len(envVars) > 0 {
+		cmd.Env = os.Environ()
+		cmd.Env = append(cmd.Env, envVars...)
+	}
+
+	msg := fmt.Sprintf("env vars: %s; command: '%s'; args: %q\n", envVars, elements[0], elements[1:])
+	log.Println(msg)
+
+	return cmd
+}
+
+// Reports whether an argument is a NAME=value environment variable
+func isEnvVar(arg string) bool {
+	i := strings.Index(arg, "=")
+	if i <= 0 {
+		return false
+	}
+	for _, c := range arg[:i] {
+		if !(c == '_' || c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z' || c >= '0' && c <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// Detach makes the command outlive the launcher: it gets a session of its own,
+// so closing the terminal the launcher was started from doesn't take it down,
+// and stdio goes to /dev/null instead of the launcher's.
+func Detach(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = nil, nil, nil
+}
+
+// SetActivationToken hands an xdg-activation token to the command, so that
+// the app may take focus from the launcher once its window shows. GTK and Qt
+// read XDG_ACTIVATION_TOKEN, older toolkits DESKTOP_STARTUP_ID.
+func SetActivationToken(cmd *exec.Cmd, token string) {
+	if token == "" {
+		return
+	}
+	if cmd.Env == nil {
+		cmd.Env = os.Environ()
+	}
+	// the last value of a variable wins, tokens the launcher was started
+	// with are used up
+	cmd.Env = append(cmd.Env, "XDG_ACTIVATION_TOKEN="+token, "DESKTOP_STARTUP_ID="+token)
+}
+
+// Reap waits for a started command in the background, so that it doesn't stay
+// around as a zombie once it exits while the launcher keeps running
+func Reap(cmd *exec.Cmd) {
+	go cmd.Wait()
+}
+
+// Expands the field codes in the arguments of an Exec line. %f and %u become
+// the first of the files, standalone %F and %U all of them, and they go away
+// without files, with the deprecated codes. A standalone %i becomes
+// "--icon <icon>", %c the name, %k the location of the desktop file and %% a
+// percent sign. Arguments left empty are dropped, and percent signs not
+// starting a field code are kept as typed: "--scale 100%" stays intact.
+func expandFieldCodes(args []string, entry Entry) []string {
+	var expanded []string
+	for _, arg := range args {
+		switch arg {
+		case "%i":
+			if entry.Icon != "" {
+				expanded = append(expanded, "--icon", entry.Icon)
+			}
+			continue
+		case "%F", "%U":
+			expanded = append(expanded, entry.Files...)
+			continue
+		}
+
+		var b strings.Builder
+		codes := false
+		for i := 0; i < len(arg); i++ {
+			if arg[i] != '%' || i+1 == len(arg) {
+				b.WriteByte(arg[i])
+				continue
+			}
+			switch arg[i+1] {
+			case '%':
+				b.WriteByte('%')
+			case 'c':
+				b.WriteString(entry.Name)
+			case 'i':
+				b.WriteString(entry.Icon)
+			case 'k':
+				b.WriteString(entry.Path)
+			case 'f', 'u':
+				if len(entry.Files) > 0 {
+					b.WriteString(entry.Files[0])
+				}
+			case 'F', 'U', 'd', 'D', 'n', 'N', 'v', 'm':
+			default:
+				b.WriteByte('%')
+				continue
+			}
+			codes = true
+			i++
+		}
+		if b.Len() == 0 && codes {
+			continue
+		}
+		expanded = append(expanded, b.String())
+	}
+	return expanded
+}
+
+// Describe returns the fully resolved argv, the environment variables added to
+// the launcher's own and the working directory of the command
+func Describe(cmd *exec.Cmd) string {
+	var env []string
+	if len(cmd.Env) > len(os.Environ()) {
+		env = cmd.Env[len(os.Environ()):]
+	}
+
+	cwd := cmd.Dir
+	if cwd == "" {
+		cwd, _ = os.Getwd()
+	}
+	return fmt.Sprintf("argv: %q\nenv: %q\ncwd: %s\n", cmd.Args, env, cwd)
+}
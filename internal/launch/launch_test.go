@@ -0,0 +1,142 @@
+package launch
+
+import (
+	"reflect"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestCommand(t *testing.T) {
+	cmd := Command("firefox --new-window %u", Entry{}, false, "foot")
+	if !reflect.DeepEqual(cmd.Args, []string{"firefox", "--new-window"}) {
+		t.Errorf("failed to strip field codes, got %q", cmd.Args)
+	}
+
+	cmd = Command("GDK_BACKEND=wayland gimp", Entry{}, false, "foot")
+	if cmd.Args[0] != "gimp" {
+		t.Errorf("failed to skip env variables, got %q", cmd.Args)
+	}
+	if cmd.Env[len(cmd.Env)-1] != "GDK_BACKEND=wayland" {
+		t.Errorf("failed to set env variables, got %q", cmd.Env[len(cmd.Env)-1])
+	}
+
+	cmd = Command(`GDK_BACKEND=wayland sh -c "FOO=1 prime-run program --flag"`, Entry{}, false, "foot")
+	if !reflect.DeepEqual(cmd.Args, []string{"sh", "-c", "FOO=1 prime-run program --flag"}) {
+		t.Errorf("failed to keep quoted arguments intact, got %q", cmd.Args)
+	}
+	if cmd.Env[len(cmd.Env)-1] != "GDK_BACKEND=wayland" {
+		t.Errorf("only leading variables are environment, got %q", cmd.Env[len(cmd.Env)-1])
+	}
+
+	cmd = Command("env BAMF_DESKTOP_FILE_HINT=/var/lib/snapd/desktop/applications/firefox_firefox.desktop /snap/bin/firefox %u", Entry{}, false, "foot")
+	if !reflect.DeepEqual(cmd.Args, []string{"/snap/bin/firefox"}) {
+		t.Errorf("failed to skip env, got %q", cmd.Args)
+	}
+	if cmd.Env[len(cmd.Env)-1] != "BAMF_DESKTOP_FILE_HINT=/var/lib/snapd/desktop/applications/firefox_firefox.desktop" {
+		t.Errorf("failed to set env variables behind env, got %q", cmd.Env[len(cmd.Env)-1])
+	}
+
+	cmd = Command("htop --tree", Entry{}, true, "foot")
+	if !reflect.DeepEqual(cmd.Args, []string{"foot", "-e", "htop", "--tree"}) {
+		t.Errorf("failed to run in terminal, got %q", cmd.Args)
+	}
+
+	cmd = Command("htop", Entry{}, true, "foot")
+	if !reflect.DeepEqual(cmd.Args, []string{"foot", "-e", "htop"}) {
+		t.Errorf("failed to run in terminal, got %q", cmd.Args)
+	}
+}
+
+func TestDetach(t *testing.T) {
+	cmd := Command("sleep 0", Entry{}, false, "foot")
+	if cmd.SysProcAttr == nil || !cmd.SysProcAttr.Setsid {
+		t.Fatal("expected the command to get a session of its own")
+	}
+	if err := cmd.Run(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSetActivationToken(t *testing.T) {
+	cmd := Command("GDK_BACKEND=wayland gimp", Entry{}, false, "foot")
+	SetActivationToken(cmd, "")
+	if cmd.Env[len(cmd.Env)-1] != "GDK_BACKEND=wayland" {
+		t.Errorf("an empty token shouldn't be set, got %q", cmd.Env[len(cmd.Env)-1])
+	}
+
+	cmd = Command("gimp", Entry{}, false, "foot")
+	SetActivationToken(cmd, "abc123")
+	if !reflect.DeepEqual(cmd.Env[len(cmd.Env)-2:], []string{"XDG_ACTIVATION_TOKEN=abc123", "DESKTOP_STARTUP_ID=abc123"}) {
+		t.Errorf("failed to set the token, got %q", cmd.Env[len(cmd.Env)-2:])
+	}
+	if !strings.Contains(Describe(cmd), "XDG_ACTIVATION_TOKEN=abc123") {
+		t.Errorf("expected the token in the description, got:\n%s", Describe(cmd))
+	}
+}
+
+func TestReap(t *testing.T) {
+	cmd := Command("true", Entry{}, false, "foot")
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	Reap(cmd)
+	// a zombie can still be signalled, a reaped process can't
+	deadline := time.Now().Add(5 * time.Second)
+	for syscall.Kill(cmd.Process.Pid, 0) == nil {
+		if time.Now().After(deadline) {
+			t.Fatal("the command wasn't reaped")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestExpandFieldCodes(t *testing.T) {
+	entry := Entry{Name: "Zoom", Icon: "zoom", Path: "/usr/share/applications/zoom.desktop"}
+	for _, tt := range []struct {
+		exec string
+		want []string
+	}{
+		{"firefox %u --new-window", []string{"firefox", "--new-window"}},
+		{"gimp %F", []string{"gimp"}},
+		{"foo --scale 100%", []string{"foo", "--scale", "100%"}},
+		{"foo %i %c %k", []string{"foo", "--icon", "zoom", "Zoom", "/usr/share/applications/zoom.desktop"}},
+		{"foo --title=%c --done=100%%", []string{"foo", "--title=Zoom", "--done=100%"}},
+		{"foo %x", []string{"foo", "%x"}},
+	} {
+		if got := expandFieldCodes(strings.Split(tt.exec, " "), entry); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%q: got %q, expected %q", tt.exec, got, tt.want)
+		}
+	}
+
+	files := Entry{Files: []string{"/tmp/a b.png", "/tmp/c.png"}}
+	for _, tt := range []struct {
+		exec string
+		want []string
+	}{
+		{"gimp %F", []string{"gimp", "/tmp/a b.png", "/tmp/c.png"}},
+		{"eog %f", []string{"eog", "/tmp/a b.png"}},
+		{"foo --open=%u", []string{"foo", "--open=/tmp/a b.png"}},
+	} {
+		if got := expandFieldCodes(strings.Split(tt.exec, " "), files); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%q with files: got %q, expected %q", tt.exec, got, tt.want)
+		}
+	}
+
+	// no icon, no --icon
+	if got := expandFieldCodes([]string{"foo", "%i"}, Entry{}); !reflect.DeepEqual(got, []string{"foo"}) {
+		t.Errorf("%%i without an icon: got %q", got)
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	cmd := Command("GDK_BACKEND=wayland gimp --new-instance", Entry{Dir: "/opt/gimp"}, false, "foot")
+
+	description := Describe(cmd)
+	for _, want := range []string{`argv: ["gimp" "--new-instance"]`, `env: ["GDK_BACKEND=wayland"]`, "cwd: /opt/gimp"} {
+		if !strings.Contains(description, want) {
+			t.Errorf("expected %q in the description, got:\n%s", want, description)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package launch
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// LogSize is the size a log file is kept under. One grown past it is rotated
+// on the next launch.
+var LogSize int64 = 1 << 20
+
+// LogOutput sends the stdout and stderr of the command to name.log in dir,
+// appending to what earlier launches wrote after a line telling what was
+// started. A log grown past LogSize is moved to name.log.1 first, replacing
+// the one there. The command writes to the file itself, so it keeps logging
+// after the launcher exits. The returned file is to be closed once the command
+// started.
+func LogOutput(cmd *exec.Cmd, dir, name string) (*os.File, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	path := filepath.Join(dir, strings.ReplaceAll(name, "/", "_")+".log")
+	if info, err := os.Stat(path); err == nil && info.Size() > LogSize {
+		if err := os.Rename(path, path+".1"); err != nil {
+			return nil, err
+		}
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Fprintf(f, "--- %s %q\n", time.Now().Format(time.RFC3339), cmd.Args)
+	cmd.Stdout, cmd.Stderr = f, f
+	return f, nil
+}
@@ -0,0 +1,50 @@
+package launch
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Runs the shell command with its output logged to foo.log in dir, waiting
+// for it to exit
+func runLogged(t *testing.T, dir, command string) {
+	t.Helper()
+	cmd := exec.Command("sh", "-c", command)
+	f, err := LogOutput(cmd, dir, "foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = cmd.Start()
+	f.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Wait(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLogOutput(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "logs")
+	runLogged(t, dir, "echo out; echo err >&2")
+	runLogged(t, dir, "echo out; echo err >&2")
+
+	path := filepath.Join(dir, "foo.log")
+	contents, _ := os.ReadFile(path)
+	if strings.Count(string(contents), "---") != 2 || strings.Count(string(contents), "out\nerr\n") != 2 {
+		t.Errorf("expected the output of both launches, got %q", contents)
+	}
+
+	defer func(size int64) { LogSize = size }(LogSize)
+	LogSize = 10
+	runLogged(t, dir, "echo out")
+	if _, err := os.Stat(path + ".1"); err != nil {
+		t.Errorf("expected the log to be rotated: %s", err)
+	}
+	if contents, _ := os.ReadFile(path); strings.Count(string(contents), "---") != 1 {
+		t.Errorf("expected the last launch only, got %q", contents)
+	}
+}
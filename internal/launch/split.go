@@ -0,0 +1,64 @@
+package launch
+
+import (
+	"errors"
+	"strings"
+)
+
+// Split splits an Exec line into arguments by the quoting rules of the desktop
+// entry spec: arguments are separated by spaces, and ones containing spaces
+// or other reserved characters are in double quotes, where a backslash escapes
+// '"', '`', '$' and '\'. For the sake of the files out there, single quotes
+// work as in the shell and a backslash outside quotes escapes the character
+// after it.
+func Split(command string) ([]string, error) {
+	var args []string
+	var arg strings.Builder
+	// an argument was started, even if it's an empty "" one
+	inArg := false
+	quote := byte(0)
+	for i := 0; i < len(command); i++ {
+		c := command[i]
+		switch {
+		case quote == '\'':
+			if c == '\'' {
+				quote = 0
+			} else {
+				arg.WriteByte(c)
+			}
+		case quote == '"':
+			switch {
+			case c == '"':
+				quote = 0
+			case c == '\\' && i+1 < len(command) && strings.IndexByte("\"`$\\", command[i+1]) != -1:
+				i++
+				arg.WriteByte(command[i])
+			default:
+				arg.WriteByte(c)
+			}
+		case c == ' ' || c == '\t':
+			if inArg {
+				args = append(args, arg.String())
+				arg.Reset()
+				inArg = false
+			}
+		case c == '"' || c == '\'':
+			quote = c
+			inArg = true
+		case c == '\\' && i+1 < len(command):
+			i++
+			arg.WriteByte(command[i])
+			inArg = true
+		default:
+			arg.WriteByte(c)
+			inArg = true
+		}
+	}
+	if quote != 0 {
+		return nil, errors.New("unterminated quote in " + command)
+	}
+	if inArg {
+		args = append(args, arg.String())
+	}
+	return args, nil
+}
@@ -0,0 +1,34 @@
+package launch
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplit(t *testing.T) {
+	for _, tt := range []struct {
+		exec string
+		want []string
+	}{
+		{"firefox  --new-window %u", []string{"firefox", "--new-window", "%u"}},
+		{`sh -c "prime-run program --flag"`, []string{"sh", "-c", "prime-run program --flag"}},
+		{`"/opt/My App/app" --name=x`, []string{"/opt/My App/app", "--name=x"}},
+		{`echo "a \"quoted\" \$HOME \\ \n"`, []string{"echo", `a "quoted" $HOME \ \n`}},
+		{`sh -c 'echo "$1"' ""`, []string{"sh", "-c", `echo "$1"`, ""}},
+		{`/opt/My\ App/app`, []string{"/opt/My App/app"}},
+		{`--x="a b"c`, []string{"--x=a bc"}},
+	} {
+		got, err := Split(tt.exec)
+		if err != nil {
+			t.Errorf("%s: %s", tt.exec, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %q, expected %q", tt.exec, got, tt.want)
+		}
+	}
+
+	if _, err := Split(`sh -c "echo`); err == nil {
+		t.Error("expected an error for an unterminated quote")
+	}
+}
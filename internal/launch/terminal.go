@@ -0,0 +1,51 @@
+package launch
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// Flags terminal emulators take before the command to run in them. Unknown
+// ones get -e, which most understand.
+var execFlags = map[string]string{
+	"alacritty":      "-e",
+	"foot":           "-e",
+	"footclient":     "-e",
+	"ghostty":        "-e",
+	"gnome-terminal": "--",
+	"kgx":            "--",
+	"kitty":          "--",
+	"konsole":        "-e",
+	"lxterminal":     "-e",
+	"ptyxis":         "--",
+	"st":             "-e",
+	"terminator":     "-x",
+	"tilix":          "-e",
+	"urxvt":          "-e",
+	"wezterm":        "start --",
+	"xfce4-terminal": "-x",
+	"xterm":          "-e",
+}
+
+// TerminalCommand returns the argv starting a command in the term terminal
+// emulator: term, which may come with arguments of its own, followed by the
+// flag it takes before the command. A term ending with the flag already is
+// left as it is.
+func TerminalCommand(term string) []string {
+	args, err := Split(term)
+	if err != nil || len(args) == 0 {
+		args = strings.Fields(term)
+	}
+	if len(args) == 0 {
+		return []string{term}
+	}
+	flag, ok := execFlags[filepath.Base(args[0])]
+	if !ok {
+		flag = "-e"
+	}
+	flagArgs := strings.Fields(flag)
+	if len(args) >= len(flagArgs)+1 && strings.Join(args[len(args)-len(flagArgs):], " ") == flag {
+		return args
+	}
+	return append(args, flagArgs...)
+}
@@ -0,0 +1,30 @@
+package launch
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTerminalCommand(t *testing.T) {
+	for _, tt := range []struct {
+		term string
+		want []string
+	}{
+		{"foot", []string{"foot", "-e"}},
+		{"/usr/bin/kitty", []string{"/usr/bin/kitty", "--"}},
+		{"gnome-terminal --window", []string{"gnome-terminal", "--window", "--"}},
+		{"wezterm", []string{"wezterm", "start", "--"}},
+		{"wezterm start --", []string{"wezterm", "start", "--"}},
+		{"alacritty -e", []string{"alacritty", "-e"}},
+		{"someterm", []string{"someterm", "-e"}},
+	} {
+		if got := TerminalCommand(tt.term); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%q: got %q, want %q", tt.term, got, tt.want)
+		}
+	}
+
+	cmd := Command(`htop --sort-key "PERCENT_CPU"`, Entry{}, true, "kitty")
+	if !reflect.DeepEqual(cmd.Args, []string{"kitty", "--", "htop", "--sort-key", "PERCENT_CPU"}) {
+		t.Errorf("failed to pass the whole command line, got %q", cmd.Args)
+	}
+}
@@ -0,0 +1,87 @@
+package launch
+
+import (
+	"io/ioutil"
+	"strconv"
+	"strings"
+)
+
+// WaylandHints returns suggestions making an app found running under XWayland
+// use Wayland natively: environment variables to prepend to its Exec line, or
+// command line flags to append. The toolkit is guessed from the Exec line.
+func WaylandHints(command string) []string {
+	lower := strings.ToLower(command)
+	for _, hint := range []struct {
+		names []string
+		hints []string
+	}{
+		{[]string{"firefox", "thunderbird", "librewolf"}, []string{"MOZ_ENABLE_WAYLAND=1"}},
+		{[]string{"chrom", "electron", "code", "codium", "discord", "slack", "signal", "obsidian"},
+			[]string{"--ozone-platform=wayland", "ELECTRON_OZONE_PLATFORM_HINT=auto"}},
+		{[]string{"java"}, []string{"_JAVA_AWT_WM_NONREPARENTING=1"}},
+	} {
+		for _, name := range hint.names {
+			if strings.Contains(lower, name) {
+				return hint.hints
+			}
+		}
+	}
+	return []string{"QT_QPA_PLATFORM=wayland", "GDK_BACKEND=wayland", "SDL_VIDEODRIVER=wayland"}
+}
+
+// WithHints returns the Exec line with the hints of WaylandHints applied: the
+// environment variables set through env, the flags added before the field
+// codes, or at the end without any
+func WithHints(command string, hints []string) string {
+	var env, flags []string
+	for _, hint := range hints {
+		if strings.HasPrefix(hint, "-") {
+			flags = append(flags, hint)
+		} else {
+			env = append(env, hint)
+		}
+	}
+
+	if len(flags) > 0 {
+		at := len(command)
+		for i := 1; i+2 <= len(command); i++ {
+			standalone := command[i-1] == ' ' && (i+2 == len(command) || command[i+2] == ' ')
+			if standalone && command[i] == '%' && strings.IndexByte("fFuU", command[i+1]) >= 0 {
+				at = i - 1
+				break
+			}
+		}
+		command = command[:at] + " " + strings.Join(flags, " ") + command[at:]
+	}
+	if len(env) > 0 {
+		command = "env " + strings.Join(env, " ") + " " + command
+	}
+	return command
+}
+
+// DescendsFrom reports whether the process pid is ancestor or one of its
+// descendants, as apps are often started through wrapper scripts
+func DescendsFrom(pid, ancestor int) bool {
+	for pid > 1 {
+		if pid == ancestor {
+			return true
+		}
+		pid = parentPid(pid)
+	}
+	return false
+}
+
+// Reads the parent PID from /proc/<pid>/stat, 0 if the process is gone
+func parentPid(pid int) int {
+	stat, err := ioutil.ReadFile("/proc/" + strconv.Itoa(pid) + "/stat")
+	if err != nil {
+		return 0
+	}
+	// "pid (comm) state ppid ...", comm may contain spaces and parentheses
+	fields := strings.Fields(string(stat[strings.LastIndexByte(string(stat), ')')+1:]))
+	if len(fields) < 2 {
+		return 0
+	}
+	ppid, _ := strconv.Atoi(fields[1])
+	return ppid
+}
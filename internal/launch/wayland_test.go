@@ -0,0 +1,51 @@
+package launch
+
+import (
+	"os"
+	"os/exec"
+	"testing"
+)
+
+func TestWaylandHints(t *testing.T) {
+	if hints := WaylandHints("/usr/bin/firefox %u"); hints[0] != "MOZ_ENABLE_WAYLAND=1" {
+		t.Errorf("unexpected hints for firefox: %q", hints)
+	}
+	if hints := WaylandHints("/usr/share/code/code --unity-launch %F"); hints[0] != "--ozone-platform=wayland" {
+		t.Errorf("unexpected hints for an electron app: %q", hints)
+	}
+	if hints := WaylandHints("krita"); len(hints) == 0 {
+		t.Error("no generic hints")
+	}
+}
+
+func TestWithHints(t *testing.T) {
+	for _, tt := range []struct {
+		command string
+		hints   []string
+		want    string
+	}{
+		{"/usr/bin/firefox %u", []string{"MOZ_ENABLE_WAYLAND=1"}, "env MOZ_ENABLE_WAYLAND=1 /usr/bin/firefox %u"},
+		{"code --unity-launch %F", []string{"--ozone-platform=wayland", "ELECTRON_OZONE_PLATFORM_HINT=auto"},
+			"env ELECTRON_OZONE_PLATFORM_HINT=auto code --unity-launch --ozone-platform=wayland %F"},
+		{"discord", []string{"--ozone-platform=wayland"}, "discord --ozone-platform=wayland"},
+	} {
+		if got := WithHints(tt.command, tt.hints); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.command, got, tt.want)
+		}
+	}
+}
+
+func TestDescendsFrom(t *testing.T) {
+	cmd := exec.Command("sleep", "1")
+	if err := cmd.Start(); err != nil {
+		t.Skip(err)
+	}
+	defer cmd.Process.Kill()
+
+	if !DescendsFrom(cmd.Process.Pid, os.Getpid()) {
+		t.Error("child not found to descend from the test process")
+	}
+	if DescendsFrom(os.Getpid(), cmd.Process.Pid) {
+		t.Error("parent found to descend from its child")
+	}
+}
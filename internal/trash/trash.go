@@ -0,0 +1,74 @@
+// Package trash moves files to the user's trash, following the freedesktop.org
+// Trash specification, so file managers can restore them too.
+package trash
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Dir returns the home trash directory
+func Dir() string {
+	if os.Getenv("XDG_DATA_HOME") != "" {
+		return filepath.Join(os.Getenv("XDG_DATA_HOME"), "Trash")
+	}
+	return filepath.Join(os.Getenv("HOME"), ".local", "share", "Trash")
+}
+
+// Trash moves the file at path to the home trash and returns its path in
+// there. The file must be on the same filesystem as the trash.
+func Trash(path string) (string, error) {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	files := filepath.Join(Dir(), "files")
+	info := filepath.Join(Dir(), "info")
+	for _, dir := range []string{files, info} {
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return "", err
+		}
+	}
+
+	// the info file is created exclusively first, which reserves the name
+	base := filepath.Base(path)
+	name := base
+	var infoFile *os.File
+	for i := 2; ; i++ {
+		infoFile, err = os.OpenFile(filepath.Join(info, name+".trashinfo"), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+		if err == nil {
+			break
+		} else if !os.IsExist(err) {
+			return "", err
+		}
+		ext := filepath.Ext(base)
+		name = fmt.Sprintf("%s.%d%s", strings.TrimSuffix(base, ext), i, ext)
+	}
+
+	_, err = fmt.Fprintf(infoFile, "[Trash Info]\nPath=%s\nDeletionDate=%s\n",
+		(&url.URL{Path: path}).EscapedPath(), time.Now().Format("2006-01-02T15:04:05"))
+	infoFile.Close()
+	if err == nil {
+		err = os.Rename(path, filepath.Join(files, name))
+	}
+	if err != nil {
+		os.Remove(filepath.Join(info, name+".trashinfo"))
+		return "", err
+	}
+	return filepath.Join(files, name), nil
+}
+
+// Restore moves a file returned by Trash back to its original path
+func Restore(trashed, original string) error {
+	if _, err := os.Stat(original); err == nil {
+		return fmt.Errorf("%s exists", original)
+	}
+	if err := os.Rename(trashed, original); err != nil {
+		return err
+	}
+	return os.Remove(filepath.Join(Dir(), "info", filepath.Base(trashed)+".trashinfo"))
+}
@@ -0,0 +1,52 @@
+package trash
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTrashAndRestore(t *testing.T) {
+	dataHome := t.TempDir()
+	t.Setenv("XDG_DATA_HOME", dataHome)
+	path := filepath.Join(dataHome, "applications", "my app.desktop")
+	os.MkdirAll(filepath.Dir(path), 0755)
+
+	var trashed []string
+	for i := 0; i < 2; i++ {
+		if err := ioutil.WriteFile(path, []byte("[Desktop Entry]\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		p, err := Trash(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		trashed = append(trashed, p)
+	}
+	if trashed[0] == trashed[1] {
+		t.Errorf("same trash name for both files: %s", trashed[0])
+	}
+	if filepath.Base(trashed[1]) != "my app.2.desktop" {
+		t.Errorf("unexpected name for the second file: %s", trashed[1])
+	}
+
+	info, err := ioutil.ReadFile(filepath.Join(Dir(), "info", "my app.desktop.trashinfo"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(info), "Path="+filepath.Dir(path)+"/my%20app.desktop\n") {
+		t.Errorf("unexpected info file:\n%s", info)
+	}
+
+	if err := Restore(trashed[0], path); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Error("file not restored")
+	}
+	if err := Restore(trashed[1], path); err == nil {
+		t.Error("restored over an existing file")
+	}
+}
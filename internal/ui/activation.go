@@ -0,0 +1,63 @@
+package ui
+
+// #cgo pkg-config: gdk-3.0 gio-unix-2.0
+// #include <stdlib.h>
+// #include <gdk/gdk.h>
+// #include <gio/gdesktopappinfo.h>
+//
+// // Asks GDK for a startup ID: an xdg-activation token on Wayland, on X11 a
+// // startup notification sequence it announces, for the app of desktop_file.
+// // X11 needs the desktop file, Wayland does without. NULL when there's none.
+// static char *startup_id(const char *desktop_file) {
+// 	GdkDisplay *display = gdk_display_get_default();
+// 	if (display == NULL)
+// 		return NULL;
+// 	GDesktopAppInfo *info = NULL;
+// 	if (desktop_file != NULL)
+// 		info = g_desktop_app_info_new_from_filename(desktop_file);
+// 	GdkAppLaunchContext *context = gdk_display_get_app_launch_context(display);
+// 	char *id = g_app_launch_context_get_startup_notify_id(G_APP_LAUNCH_CONTEXT(context), (GAppInfo *) info, NULL);
+// 	g_object_unref(context);
+// 	if (info != NULL)
+// 		g_object_unref(info);
+// 	return id;
+// }
+import "C"
+
+import (
+	"unsafe"
+
+	"github.com/ftphikari/wlaunchpad/internal/config"
+	"github.com/ftphikari/wlaunchpad/internal/entries"
+)
+
+func init() {
+	config.AddFeature("window", "xdg-activation")
+	config.AddFeature("window", "startup-notification")
+}
+
+// Returns a token letting the app of entry take focus, and for entries with
+// StartupNotify=true telling bars and compositors it is starting, "" when
+// there's none to give. Tokens are tied to the last input event and the
+// focused surface, so ask for one before hiding the window, on the main
+// thread.
+func activationToken(entry entries.DesktopEntry) string {
+	if settings.DryRun {
+		return ""
+	}
+	var desktopFile *C.char
+	if entry.StartupNotify && entry.Path != "" {
+		desktopFile = C.CString(entry.Path)
+		defer C.free(unsafe.Pointer(desktopFile))
+	} else if !wayland() {
+		// on X11 only apps saying so end startup sequences, others would
+		// leave a busy cursor until it times out
+		return ""
+	}
+	token := C.startup_id(desktopFile)
+	if token == nil {
+		return ""
+	}
+	defer C.free(unsafe.Pointer(token))
+	return C.GoString(token)
+}
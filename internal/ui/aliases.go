@@ -0,0 +1,57 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/ftphikari/wlaunchpad/internal/entries"
+)
+
+// Returns the entries given alternate names starting with the search phrase,
+// in the [aliases] section of the config file:
+//
+//	[aliases]
+//	gimp = "org.gimp.GIMP"
+//	word = ["onlyoffice-desktopeditors", "libreoffice-writer"]
+//
+// Values are desktop IDs, the ".desktop" suffix may be left out.
+func aliasedEntries(snapshot *entries.Snapshot, searchPhrase string) []entries.DesktopEntry {
+	if searchPhrase == "" {
+		return nil
+	}
+	searchPhrase = strings.ToLower(searchPhrase)
+
+	var ids []string
+	for _, alias := range cfg.Keys("aliases") {
+		if !strings.HasPrefix(strings.ToLower(alias), searchPhrase) {
+			continue
+		}
+		targets := cfg.List("aliases", alias)
+		if targets == nil {
+			targets = []string{cfg.Str("aliases", alias, "")}
+		}
+		for _, id := range targets {
+			if !strings.HasSuffix(id, ".desktop") {
+				id += ".desktop"
+			}
+			ids = append(ids, id)
+		}
+	}
+
+	var aliased []entries.DesktopEntry
+	for _, id := range ids {
+		entry, ok := snapshot.Lookup(id)
+		if ok && !entry.NoDisplay && !containsEntry(aliased, id) {
+			aliased = append(aliased, entry)
+		}
+	}
+	return aliased
+}
+
+func containsEntry(list []entries.DesktopEntry, id string) bool {
+	for _, entry := range list {
+		if entry.DesktopID == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,163 @@
+package ui
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gotk3/gotk3/gtk"
+
+	"github.com/ftphikari/wlaunchpad/internal/config"
+	"github.com/ftphikari/wlaunchpad/internal/entries"
+)
+
+const brokenIconStyle = `
+.broken-icon {
+	color: alpha(currentColor, 0.6);
+	font-size: 85%;
+}
+`
+
+var (
+	// Icon name -> why it couldn't be loaded, for icons in iconCache
+	iconErrors = make(map[string]error)
+	// State ID without ".desktop" -> image file chosen in its place
+	iconOverrides map[string]string
+)
+
+// Returns the directory of icons chosen for entries, named after their
+// component IDs, or desktop IDs for apps without one: org.mozilla.firefox.png,
+// foot.png for foot.desktop. Icons named after the desktop ID of an app with
+// a component ID are used as well, they were saved by earlier versions.
+func iconOverrideDir() string {
+	return filepath.Join(config.Dir(), "icons")
+}
+
+func loadIconOverrides() {
+	iconOverrides = make(map[string]string)
+	files, err := ioutil.ReadDir(iconOverrideDir())
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Printf("Couldn't read icon overrides: %s", err)
+		}
+		return
+	}
+	for _, f := range files {
+		name := f.Name()
+		iconOverrides[strings.TrimSuffix(name, filepath.Ext(name))] = filepath.Join(iconOverrideDir(), name)
+	}
+}
+
+// Returns the image file chosen for the entry
+func iconOverride(entry entries.DesktopEntry) (string, bool) {
+	for _, id := range []string{entry.StateID(), entry.DesktopID} {
+		if path, ok := iconOverrides[strings.TrimSuffix(id, ".desktop")]; ok {
+			return path, true
+		}
+	}
+	return "", false
+}
+
+// Returns the icon shown for the entry
+func entryIcon(entry entries.DesktopEntry) string {
+	if path, ok := iconOverride(entry); ok {
+		return path
+	}
+	return entry.Icon
+}
+
+// Shows the broken-icon badge if the icon of the button's entry failed to load
+func markBrokenIcon(ab *appButton) {
+	if err, ok := iconErrors[entryIcon(ab.entry)]; ok {
+		ab.broken.SetTooltipText(fmt.Sprintf("Couldn't load icon: %s", err))
+		ab.broken.Show()
+	} else {
+		ab.broken.Hide()
+	}
+}
+
+// Loads the icon of the entry again, e.g. after the missing theme got installed
+func retryIcon(entry entries.DesktopEntry) {
+	icon := entryIcon(entry)
+	if _, ok := iconCache[icon]; ok {
+		dropIcon(icon)
+	}
+	setUpAppsFlowBox(phrase)
+	if err, ok := iconErrors[icon]; ok {
+		statusLabel.SetText(fmt.Sprintf("Still couldn't load the icon of %s: %s", entry.NameLoc, err))
+	} else {
+		statusLabel.SetText(fmt.Sprintf("Icon of %s loaded", entry.NameLoc))
+	}
+}
+
+// Lets the user pick an image for the entry in a popover over its button. The
+// window is a layer surface, so a file chooser dialog could end up behind it.
+func chooseIcon(ab *appButton) {
+	entry := ab.entry
+	popover, _ := gtk.PopoverNew(ab)
+	popover.SetPosition(gtk.POS_BOTTOM)
+
+	box, _ := gtk.BoxNew(gtk.ORIENTATION_VERTICAL, 6)
+	box.SetMarginStart(10)
+	box.SetMarginEnd(10)
+	box.SetMarginTop(10)
+	box.SetMarginBottom(10)
+	popover.Add(box)
+
+	chooser, _ := gtk.FileChooserWidgetNew(gtk.FILE_CHOOSER_ACTION_OPEN)
+	chooser.SetSizeRequest(640, 400)
+	filter, _ := gtk.FileFilterNew()
+	filter.SetName("Images")
+	filter.AddPixbufFormats()
+	chooser.AddFilter(filter)
+	box.PackStart(chooser, true, true, 0)
+
+	useButton, _ := gtk.ButtonNewWithLabel("Use icon")
+	useButton.SetHAlign(gtk.ALIGN_END)
+	box.PackStart(useButton, false, false, 0)
+
+	use := func() {
+		file := chooser.GetFilename()
+		if file == "" {
+			return
+		}
+		popover.Popdown()
+		if err := setIconOverride(entry, file); err != nil {
+			log.Printf("Couldn't set the icon of %s: %s", entry.DesktopID, err)
+			statusLabel.SetText(fmt.Sprintf("Couldn't set the icon of %s: %s", entry.NameLoc, err))
+			return
+		}
+		loadIconOverrides()
+		retryIcon(entry)
+	}
+	useButton.Connect("clicked", use)
+	chooser.Connect("file-activated", use)
+
+	popover.Connect("closed", func() {
+		popover.Destroy()
+	})
+	box.ShowAll()
+	popover.Popup()
+}
+
+// Copies the image into the override directory, replacing an earlier one
+func setIconOverride(entry entries.DesktopEntry, file string) error {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	id := entry.StateID()
+	stem := strings.TrimSuffix(id, ".desktop")
+	if old, ok := iconOverride(entry); ok {
+		if _, cached := iconCache[old]; cached {
+			dropIcon(old)
+		}
+		os.Remove(old)
+	}
+	path := filepath.Join(iconOverrideDir(), stem+strings.ToLower(filepath.Ext(file)))
+	log.Printf("Icon of %s set to %s\n", id, path)
+	return config.WriteFile(path, data, 0644)
+}
@@ -0,0 +1,174 @@
+package ui
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"os/exec"
+	"strings"
+
+	"github.com/gotk3/gotk3/glib"
+	"github.com/gotk3/gotk3/gtk"
+
+	"github.com/ftphikari/wlaunchpad/internal/config"
+	"github.com/ftphikari/wlaunchpad/internal/entries"
+	"github.com/ftphikari/wlaunchpad/internal/launch"
+)
+
+func init() {
+	// entries besides the desktop files
+	config.AddFeature("providers", "screenshot")
+	config.AddFeature("providers", "color-picker")
+	config.AddFeature("providers", "session-sets")
+}
+
+// Time given to the compositor to unmap the window before capturing the screen
+const hideDelay = 250
+
+var screenshotActions = []struct {
+	key, name, comment, icon, command string
+}{
+	{"region", "Screenshot (region)", "Capture a selected region of the screen", "applets-screenshooter",
+		`grim -g "$(slurp)" "$(xdg-user-dir PICTURES)/$(date +%Y-%m-%d-%H%M%S).png"`},
+	{"window", "Screenshot (window)", "Capture a selected window", "applets-screenshooter",
+		`grim -g "$(swaymsg -t get_tree | jq -r '.. | select(.pid? and .visible?) | .rect | "\(.x),\(.y) \(.width)x\(.height)"' | slurp)" "$(xdg-user-dir PICTURES)/$(date +%Y-%m-%d-%H%M%S).png"`},
+	{"screen", "Screenshot (full screen)", "Capture all outputs", "applets-screenshooter",
+		`grim "$(xdg-user-dir PICTURES)/$(date +%Y-%m-%d-%H%M%S).png"`},
+	{"record", "Screen recording", "Start recording a selected region, launch again to stop", "media-record",
+		`pkill -INT -x wf-recorder || wf-recorder -g "$(slurp)" -f "$(xdg-user-dir VIDEOS)/$(date +%Y-%m-%d-%H%M%S).mp4"`},
+}
+
+// Returns the screen capture entries. Every command template can be
+// overridden in the [screenshot] section of the config file under its key
+// (region, window, screen, record), an empty one removes the entry.
+func screenshotEntries([]entries.DesktopEntry) []entries.DesktopEntry {
+	var screenshots []entries.DesktopEntry
+	for _, a := range screenshotActions {
+		command := cfg.Str("screenshot", a.key, a.command)
+		if command == "" {
+			continue
+		}
+		screenshots = append(screenshots, entries.DesktopEntry{
+			DesktopID:  "screenshot:" + a.key,
+			Name:       a.name,
+			NameLoc:    a.name,
+			Comment:    a.comment,
+			CommentLoc: a.comment,
+			Icon:       a.icon,
+			Exec:       command,
+			Category:   "X-Screenshot",
+			Action: func() {
+				runHidden(command)
+			},
+		})
+	}
+	return screenshots
+}
+
+// Hides the window and runs a shell command once it's gone from the screen
+func runHidden(command string) {
+	win.Hide()
+	glib.TimeoutAdd(hideDelay, func() bool {
+		cmd := exec.Command("sh", "-c", command)
+		launch.Detach(cmd)
+		if settings.DryRun {
+			fmt.Print(launch.Describe(cmd))
+		} else if err := cmd.Start(); err != nil {
+			log.Print(err)
+		} else {
+			launch.Reap(cmd)
+		}
+		if !settings.Daemon {
+			gtk.MainQuit()
+		}
+		return false
+	})
+}
+
+// Returns the "Pick color" entry
+func colorPickerEntries([]entries.DesktopEntry) []entries.DesktopEntry {
+	return []entries.DesktopEntry{{
+		DesktopID:  "colorpicker",
+		Name:       "Pick color",
+		NameLoc:    "Pick color",
+		Comment:    "Copy the hex value of a color on the screen to the clipboard",
+		CommentLoc: "Copy the hex value of a color on the screen to the clipboard",
+		Icon:       "color-select",
+		Category:   "X-ColorPicker",
+		Action: func() {
+			win.Hide()
+			glib.TimeoutAdd(hideDelay, func() bool {
+				go pickColor()
+				return false
+			})
+		},
+	}}
+}
+
+// Lets the user pick a color with hyprpicker or, if not installed, with a
+// single-pixel grim+slurp capture. The command can be replaced in the config
+// file, it's expected to print the color as #rrggbb. In dry run mode the
+// picking command is only printed, copying depends on what it picks.
+func pickColor() {
+	var cmd *exec.Cmd
+	ppm := false
+	if command := cfg.Str("colorpicker", "command", ""); command != "" {
+		cmd = exec.Command("sh", "-c", command)
+	} else if _, err := exec.LookPath("hyprpicker"); err == nil {
+		cmd = exec.Command("hyprpicker")
+	} else {
+		cmd = exec.Command("sh", "-c", `grim -g "$(slurp -p)" -t ppm -`)
+		ppm = true
+	}
+
+	if settings.DryRun {
+		fmt.Print(launch.Describe(cmd))
+	} else {
+		out, err := cmd.Output()
+		if err == nil && ppm {
+			var pixel string
+			pixel, err = ppmPixelHex(bytes.NewReader(out))
+			out = []byte(pixel)
+		}
+
+		hex := strings.TrimSpace(string(out))
+		if err != nil || hex == "" {
+			log.Printf("Color picking failed: %v", err)
+		} else {
+			copyCmd := exec.Command("wl-copy", hex)
+			if err := copyCmd.Run(); err != nil {
+				log.Printf("Couldn't copy %s to the clipboard: %s", hex, err)
+			}
+			exec.Command("notify-send", "-i", "color-select", "Color copied to clipboard", hex).Run()
+		}
+	}
+
+	if !settings.Daemon {
+		glib.IdleAdd(func() bool {
+			gtk.MainQuit()
+			return false
+		})
+	}
+}
+
+// Returns the first pixel of a binary PPM (P6) image as #rrggbb
+func ppmPixelHex(in io.Reader) (string, error) {
+	r := bufio.NewReader(in)
+	var magic string
+	var width, height, max int
+	if _, err := fmt.Fscan(r, &magic, &width, &height, &max); err != nil {
+		return "", err
+	}
+	if magic != "P6" || max > 255 || width < 1 || height < 1 {
+		return "", fmt.Errorf("unsupported image format %s, max value %d", magic, max)
+	}
+
+	// A single whitespace separates the header from the pixel data
+	pixel := make([]byte, 4)
+	if _, err := io.ReadFull(r, pixel); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("#%02x%02x%02x", pixel[1], pixel[2], pixel[3]), nil
+}
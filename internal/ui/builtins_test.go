@@ -0,0 +1,22 @@
+package ui
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestPPMPixelHex(t *testing.T) {
+	image := append([]byte("P6\n1 1\n255\n"), 0x1a, 0x2b, 0xff)
+	hex, err := ppmPixelHex(bytes.NewReader(image))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if hex != "#1a2bff" {
+		t.Errorf("expected #1a2bff, got %s", hex)
+	}
+
+	if _, err := ppmPixelHex(bytes.NewReader([]byte("P3\n1 1\n255\n26 43 255\n"))); err == nil {
+		t.Error("expected an error for an ASCII PPM")
+	}
+}
@@ -0,0 +1,235 @@
+package ui
+
+import (
+	"log"
+	"strconv"
+
+	"github.com/gotk3/gotk3/gdk"
+	"github.com/gotk3/gotk3/gtk"
+	"github.com/gotk3/gotk3/pango"
+
+	"github.com/ftphikari/wlaunchpad/internal/entries"
+	"github.com/ftphikari/wlaunchpad/internal/ipc"
+)
+
+// Grid button. The widget tree is created once and only updated when the
+// button gets reused for another entry, so that searching doesn't allocate
+// widgets (memory GTK never gives back to the system in daemon mode).
+type appButton struct {
+	*gtk.Button
+	image    *gtk.Image
+	label    *gtk.Label
+	generic  *gtk.Label
+	badge    *gtk.Label
+	broken   *gtk.Label
+	progress *gtk.ProgressBar
+	entry    entries.DesktopEntry
+}
+
+var (
+	gridButtons []*appButton // currently in appFlowBox
+	freeButtons []*appButton // detached from appFlowBox, ready for reuse
+	liveWidgets int          // created by us and not destroyed yet
+	livePixbufs int          // in iconCache
+)
+
+func newAppButton() *appButton {
+	ab := &appButton{}
+	ab.Button, _ = gtk.ButtonNew()
+
+	ab.image, _ = gtk.ImageNew()
+	overlay, _ := gtk.OverlayNew()
+	overlay.Add(ab.image)
+
+	// unread-count badge over the top right corner of the icon
+	ab.badge, _ = gtk.LabelNew("")
+	ab.badge.SetNoShowAll(true)
+	ab.badge.SetHAlign(gtk.ALIGN_END)
+	ab.badge.SetVAlign(gtk.ALIGN_START)
+	ctx, _ := ab.badge.GetStyleContext()
+	ctx.AddClass("badge")
+	overlay.AddOverlay(ab.badge)
+
+	// over the bottom left corner when the icon failed to load
+	ab.broken, _ = gtk.LabelNew("⚠")
+	ab.broken.SetNoShowAll(true)
+	ab.broken.SetHAlign(gtk.ALIGN_START)
+	ab.broken.SetVAlign(gtk.ALIGN_END)
+	ctx, _ = ab.broken.GetStyleContext()
+	ctx.AddClass("broken-icon")
+	overlay.AddOverlay(ab.broken)
+
+	// shown only while the application reports progress over LauncherEntry
+	ab.progress, _ = gtk.ProgressBarNew()
+	ab.progress.SetNoShowAll(true)
+
+	// Pango cuts long names at the width of the cell; a minimal natural width
+	// keeps them from widening it instead
+	ab.label, _ = gtk.LabelNew("")
+	ab.label.SetEllipsize(pango.ELLIPSIZE_END)
+	ab.label.SetMaxWidthChars(1)
+
+	ab.generic, _ = gtk.LabelNew("")
+	ab.generic.SetEllipsize(pango.ELLIPSIZE_END)
+	ab.generic.SetMaxWidthChars(1)
+	ab.generic.SetNoShowAll(true)
+	ctx, _ = ab.generic.GetStyleContext()
+	ctx.AddClass("dim-label")
+	ctx.AddClass("generic-name")
+
+	box, _ := gtk.BoxNew(gtk.ORIENTATION_VERTICAL, 2)
+	box.PackStart(overlay, false, false, 0)
+	box.PackStart(ab.progress, false, false, 0)
+	box.PackStart(ab.label, false, false, 0)
+	box.PackStart(ab.generic, false, false, 0)
+	ab.Add(box)
+
+	for _, w := range []gtk.IWidget{ab.Button, ab.image, overlay, ab.badge, ab.broken, ab.progress, ab.label, ab.generic, box} {
+		trackWidget(w)
+	}
+
+	ab.Connect("button-release-event", func(btn *gtk.Button, e *gdk.Event) bool {
+		btnEvent := gdk.EventButtonNewFromEvent(e)
+		if btnEvent.Button() == 1 {
+			ab.run()
+			return true
+		} else if btnEvent.Button() == 3 {
+			showContextMenu(ab)
+			return true
+		}
+		return false
+	})
+	ab.Connect("activate", ab.run)
+	ab.Connect("enter-notify-event", func() {
+		statusLabel.SetText(hoverText(ab.entry))
+	})
+	ab.Connect("focus-in-event", func() {
+		showPreview(ab.entry)
+	})
+	return ab
+}
+
+// Returns a button showing the entry, reusing a free one if possible
+func getAppButton(entry entries.DesktopEntry) *appButton {
+	var ab *appButton
+	if n := len(freeButtons); n > 0 {
+		ab = freeButtons[n-1]
+		freeButtons = freeButtons[:n-1]
+	} else {
+		ab = newAppButton()
+	}
+	ab.setEntry(entry)
+	gridButtons = append(gridButtons, ab)
+	return ab
+}
+
+func (ab *appButton) setEntry(entry entries.DesktopEntry) {
+	ab.entry = entry
+	ab.setIcon()
+
+	ab.label.SetText(entry.NameLoc)
+	ab.label.SetSizeRequest(labelWidth(iconSize), -1)
+	if genericNames() == "label" && entry.GenericNameLoc != "" && entry.GenericNameLoc != entry.NameLoc {
+		ab.generic.SetText(entry.GenericNameLoc)
+		ab.generic.SetSizeRequest(labelWidth(iconSize), -1)
+		ab.generic.Show()
+	} else {
+		ab.generic.Hide()
+	}
+
+	ab.setBadge()
+	markXWayland(ab)
+
+	if settings.LauncherEntry {
+		updateProgressBar(ab.progress, launcherEntries[entry.DesktopID])
+	} else {
+		ab.progress.Hide()
+	}
+}
+
+// Shows the count of notifications or LauncherEntry updates in the badge,
+// else what sets the entry apart
+func (ab *appButton) setBadge() {
+	entry := ab.entry
+	count := ipc.NotificationCount(badgeCounts, entry.DesktopID, entry.Name)
+	if state := launcherEntries[entry.DesktopID]; state.CountVisible {
+		count = state.Count
+	}
+	if count > 0 {
+		text := strconv.Itoa(count)
+		if count > 99 {
+			text = "99+"
+		}
+		ab.badge.SetText(text)
+		ab.badge.Show()
+	} else if entry.NoDisplay {
+		// only shown by hiddenFallback
+		ab.badge.SetText("HIDDEN")
+		ab.badge.Show()
+	} else if isNewApp(entry.StateID()) {
+		ab.badge.SetText("NEW")
+		ab.badge.Show()
+	} else if xwaylandApps[entry.DesktopID] {
+		ab.badge.SetText("X11")
+		ab.badge.Show()
+	} else {
+		ab.badge.Hide()
+	}
+}
+
+// Where generic names like "Web Browser" are shown, from the config file:
+//
+//	[grid]
+//	generic-names = "label" # under the name; "status" in the status line on
+//	                        # hover; "off" by default
+func genericNames() string {
+	return cfg.Str("grid", "generic-names", "off")
+}
+
+// Returns the status line text of an entry the pointer is over
+func hoverText(entry entries.DesktopEntry) string {
+	if genericNames() != "status" || entry.GenericNameLoc == "" {
+		return entry.CommentLoc
+	}
+	if entry.CommentLoc == "" {
+		return entry.GenericNameLoc
+	}
+	return entry.GenericNameLoc + " — " + entry.CommentLoc
+}
+
+func (ab *appButton) run() {
+	if needsConfirmation(ab.entry) {
+		confirmLaunch(ab)
+		return
+	}
+	launchEntry(ab.entry)
+}
+
+// Detaches all buttons from the flow boxes and puts them on the free-list
+func releaseAppButtons() {
+	for _, flowBox := range []*gtk.FlowBox{pinnedFlowBox, suggestedFlowBox, recentFlowBox, appFlowBox} {
+		if flowBox == nil {
+			continue
+		}
+		for child := flowBox.GetChildAtIndex(0); child != nil; child = flowBox.GetChildAtIndex(0) {
+			if button, err := child.GetChild(); err == nil {
+				child.Remove(button)
+			}
+			child.Destroy()
+		}
+	}
+	freeButtons = append(freeButtons, gridButtons...)
+	gridButtons = nil
+}
+
+func trackWidget(w gtk.IWidget) {
+	liveWidgets++
+	w.ToWidget().Connect("destroy", func() {
+		liveWidgets--
+	})
+}
+
+func logGridStats() {
+	log.Printf("Grid: %d buttons shown, %d free; %d live widgets, %d cached pixbufs\n",
+		len(gridButtons), len(freeButtons), liveWidgets, livePixbufs)
+}
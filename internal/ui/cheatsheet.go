@@ -0,0 +1,60 @@
+package ui
+
+import (
+	"github.com/gotk3/gotk3/gtk"
+)
+
+var cheatsheetPopover *gtk.Popover
+
+// Shows the keys in effect, remapped ones included, under the search entry
+func toggleCheatsheet() {
+	if cheatsheetPopover != nil {
+		cheatsheetPopover.Popdown()
+		return
+	}
+
+	// from the tables the keys are handled by, in the order they are
+	var rows [][2]string
+	for _, b := range bindings {
+		if len(b.keys) > 0 {
+			rows = append(rows, [2]string{b.labels(), b.description})
+		}
+	}
+	for _, k := range editingKeys {
+		rows = append(rows, [2]string{k.label, k.description})
+	}
+	for _, k := range windowKeys {
+		if k.label != "" && (k.shown == nil || k.shown()) {
+			rows = append(rows, [2]string{k.label, k.description})
+		}
+	}
+	rows = append(rows, [2]string{"Ctrl+Scroll", "Zoom"})
+
+	grid, _ := gtk.GridNew()
+	grid.SetColumnSpacing(24)
+	grid.SetRowSpacing(4)
+	grid.SetMarginStart(10)
+	grid.SetMarginEnd(10)
+	grid.SetMarginTop(10)
+	grid.SetMarginBottom(10)
+	for i, row := range rows {
+		keys, _ := gtk.LabelNew(row[0])
+		keys.SetHAlign(gtk.ALIGN_END)
+		ctx, _ := keys.GetStyleContext()
+		ctx.AddClass("dim-label")
+		description, _ := gtk.LabelNew(row[1])
+		description.SetHAlign(gtk.ALIGN_START)
+		grid.Attach(keys, 0, i, 1, 1)
+		grid.Attach(description, 1, i, 1, 1)
+	}
+
+	cheatsheetPopover, _ = gtk.PopoverNew(searchEntry)
+	cheatsheetPopover.SetPosition(gtk.POS_BOTTOM)
+	cheatsheetPopover.Add(grid)
+	cheatsheetPopover.Connect("closed", func() {
+		cheatsheetPopover.Destroy()
+		cheatsheetPopover = nil
+	})
+	grid.ShowAll()
+	cheatsheetPopover.Popup()
+}
@@ -0,0 +1,82 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/gotk3/gotk3/gtk"
+
+	"github.com/ftphikari/wlaunchpad/internal/entries"
+)
+
+// Reports whether the entry is listed in the [confirm] section of the config
+// file, by DesktopID or by a glob pattern matching its Exec line:
+//
+//	[confirm]
+//	entries = ["shutdown.desktop"]
+//	exec = ["rm *", "dd *", "systemctl poweroff*", "*reboot*"]
+func needsConfirmation(entry entries.DesktopEntry) bool {
+	for _, id := range cfg.List("confirm", "entries") {
+		if id == entry.DesktopID {
+			return true
+		}
+	}
+	for _, pattern := range cfg.List("confirm", "exec") {
+		if entry.Exec != "" && entries.MatchGlob(pattern, entry.Exec) {
+			return true
+		}
+	}
+	return false
+}
+
+// Asks in a popover over the button before launching. A dialog window would
+// end up below our overlay layer surface. Cancel has the focus, so an
+// accidental Enter doesn't launch either.
+func confirmLaunch(ab *appButton) {
+	popover, _ := gtk.PopoverNew(ab)
+	popover.SetPosition(gtk.POS_BOTTOM)
+
+	box, _ := gtk.BoxNew(gtk.ORIENTATION_VERTICAL, 6)
+	box.SetMarginStart(10)
+	box.SetMarginEnd(10)
+	box.SetMarginTop(10)
+	box.SetMarginBottom(10)
+	popover.Add(box)
+
+	question, _ := gtk.LabelNew(fmt.Sprintf("Launch %s?", ab.entry.NameLoc))
+	box.PackStart(question, false, false, 0)
+	if ab.entry.Exec != "" {
+		command, _ := gtk.LabelNew(ab.entry.Exec)
+		command.SetLineWrap(true)
+		command.SetMaxWidthChars(40)
+		ctx, _ := command.GetStyleContext()
+		ctx.AddClass("dim-label")
+		box.PackStart(command, false, false, 0)
+	}
+
+	buttons, _ := gtk.BoxNew(gtk.ORIENTATION_HORIZONTAL, 6)
+	buttons.SetHomogeneous(true)
+	box.PackStart(buttons, false, false, 0)
+
+	cancel, _ := gtk.ButtonNewWithLabel("Cancel")
+	cancel.Connect("clicked", func() {
+		popover.Popdown()
+	})
+	buttons.PackStart(cancel, true, true, 0)
+
+	confirm, _ := gtk.ButtonNewWithLabel("Launch")
+	ctx, _ := confirm.GetStyleContext()
+	ctx.AddClass("destructive-action")
+	entry := ab.entry
+	confirm.Connect("clicked", func() {
+		popover.Popdown()
+		launchEntry(entry)
+	})
+	buttons.PackStart(confirm, true, true, 0)
+
+	popover.Connect("closed", func() {
+		popover.Destroy()
+	})
+	box.ShowAll()
+	popover.Popup()
+	cancel.GrabFocus()
+}
@@ -0,0 +1,100 @@
+package ui
+
+import (
+	"github.com/gotk3/gotk3/gtk"
+)
+
+type contextItem struct {
+	label  string
+	action func()
+}
+
+// Returns the actions available for the entry of a button
+func contextItems(ab *appButton) []contextItem {
+	var items []contextItem
+	for _, action := range ab.entry.Actions {
+		entry := ab.entry
+		entry.Exec = action.Exec
+		items = append(items, contextItem{action.NameLoc, func() { launchEntry(entry) }})
+	}
+	for _, alternative := range ab.entry.Alternatives {
+		alternative := alternative
+		items = append(items, contextItem{"Launch " + alternative.NameLoc, func() { launchEntry(alternative) }})
+	}
+	if gpus.Dual() && ab.entry.Action == nil && !ab.entry.PrefersNonDefaultGPU {
+		entry := ab.entry
+		entry.PrefersNonDefaultGPU = true
+		items = append(items, contextItem{"Launch using dedicated GPU", func() { launchEntry(entry) }})
+	}
+	if xwaylandApps[ab.entry.DesktopID] {
+		items = append(items, contextItem{"Wayland hints…", func() { showWaylandHints(ab) }})
+	}
+	if _, ok := iconErrors[entryIcon(ab.entry)]; ok {
+		entry := ab.entry
+		items = append(items, contextItem{"Retry icon", func() { retryIcon(entry) }})
+	}
+	if entry := ab.entry; isPinned(entry) {
+		items = append(items, contextItem{"Unpin", func() { setPinned(entry, false) }})
+	} else {
+		items = append(items, contextItem{"Pin", func() { setPinned(entry, true) }})
+	}
+	items = append(items, contextItem{"Choose icon…", func() { chooseIcon(ab) }})
+	if canDeleteEntry(ab.entry) {
+		entry := ab.entry
+		items = append(items, contextItem{"Delete shortcut", func() { deleteEntry(entry) }})
+	}
+	return items
+}
+
+// Shows the context menu of a button, shown on right click
+func showContextMenu(ab *appButton) {
+	if items := contextItems(ab); len(items) > 0 {
+		showMenu(ab, items)
+	}
+}
+
+// Shows the items in a popover under the button
+func showMenu(ab *appButton, items []contextItem) {
+	popover, _ := gtk.PopoverNew(ab)
+	popover.SetPosition(gtk.POS_BOTTOM)
+	box, _ := gtk.BoxNew(gtk.ORIENTATION_VERTICAL, 0)
+	box.SetMarginTop(6)
+	box.SetMarginBottom(6)
+	popover.Add(box)
+
+	for _, item := range items {
+		action := item.action
+		button, _ := gtk.ButtonNewWithLabel(item.label)
+		button.SetRelief(gtk.RELIEF_NONE)
+		if label, err := button.GetChild(); err == nil {
+			label.ToWidget().SetHAlign(gtk.ALIGN_START)
+		}
+		button.Connect("clicked", func() {
+			popover.Popdown()
+			action()
+		})
+		box.PackStart(button, false, false, 0)
+	}
+
+	popover.Connect("closed", func() {
+		popover.Destroy()
+	})
+	box.ShowAll()
+	popover.Popup()
+}
+
+// Returns the merge rules from the config file, collapsing variants of an app
+// into one entry with the others in its context menu:
+//
+//	[merge]
+//	same-binary = true # merge entries running the same binary
+//
+//	[merge.alacritty]
+//	entries = ["Alacritty.desktop", "Alacritty-*.desktop"] # the first one stays
+func mergeGroups() [][]string {
+	var groups [][]string
+	for _, name := range cfg.Subsections("merge") {
+		groups = append(groups, cfg.List("merge."+name, "entries"))
+	}
+	return groups
+}
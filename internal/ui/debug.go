@@ -0,0 +1,64 @@
+package ui
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/gotk3/gotk3/gtk"
+)
+
+var matchDebugPopover *gtk.Popover
+
+// Shows why each entry in the grid matched the query, in a popover below the
+// search entry. Toggled with F12.
+func toggleMatchDebug() {
+	if matchDebugPopover != nil {
+		matchDebugPopover.Popdown()
+		return
+	}
+	if phrase == "" {
+		return
+	}
+
+	snapshot := currentEntries()
+	aliased := aliasedEntries(snapshot, phrase)
+	phrases := searchPhrases(phrase)
+	var lines []string
+	for _, ab := range gridButtons {
+		reason := "no match"
+		if containsEntry(aliased, ab.entry.DesktopID) {
+			reason = "alias"
+		} else if m, ok := scope.Find(ab.entry, phrases); ok && m.Fuzzy {
+			reason = fmt.Sprintf("fuzzy from %s at %d, %q, %d gaps", m.Field, m.Pos, m.Phrase, m.Gaps)
+		} else if ok {
+			reason = fmt.Sprintf("%s at %d, %q", m.Field, m.Pos, m.Phrase)
+		}
+		lines = append(lines, fmt.Sprintf("%-32s %-32s %s", ab.entry.DesktopID, ab.entry.NameLoc, reason))
+	}
+	if len(lines) == 0 {
+		lines = append(lines, "No entries shown")
+	}
+
+	matchDebugPopover, _ = gtk.PopoverNew(searchEntry)
+	matchDebugPopover.SetPosition(gtk.POS_BOTTOM)
+
+	scrolled, _ := gtk.ScrolledWindowNew(nil, nil)
+	scrolled.SetPolicy(gtk.POLICY_AUTOMATIC, gtk.POLICY_AUTOMATIC)
+	scrolled.SetSizeRequest(720, 360)
+	matchDebugPopover.Add(scrolled)
+
+	label, _ := gtk.LabelNew("")
+	label.SetMarkup("<tt>" + html.EscapeString(strings.Join(lines, "\n")) + "</tt>")
+	label.SetSelectable(true)
+	label.SetHAlign(gtk.ALIGN_START)
+	label.SetVAlign(gtk.ALIGN_START)
+	scrolled.Add(label)
+
+	matchDebugPopover.Connect("closed", func() {
+		matchDebugPopover.Destroy()
+		matchDebugPopover = nil
+	})
+	scrolled.ShowAll()
+	matchDebugPopover.Popup()
+}
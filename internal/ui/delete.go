@@ -0,0 +1,45 @@
+package ui
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+
+	"github.com/ftphikari/wlaunchpad/internal/entries"
+	"github.com/ftphikari/wlaunchpad/internal/trash"
+)
+
+// Only shortcuts in the user's own applications directory can be deleted,
+// system ones belong to packages
+func canDeleteEntry(entry entries.DesktopEntry) bool {
+	return entry.Path != "" && filepath.Dir(entry.Path) == entries.UserAppDir()
+}
+
+// Moves the desktop file to the trash, offering to undo it
+func deleteEntry(entry entries.DesktopEntry) {
+	trashed, err := trash.Trash(entry.Path)
+	if err != nil {
+		log.Printf("Couldn't move %s to the trash: %s", entry.Path, err)
+		statusLabel.SetText(fmt.Sprintf("Couldn't delete %s: %s", entry.NameLoc, err))
+		return
+	}
+	log.Printf("Moved %s to %s\n", entry.Path, trashed)
+	rescan()
+
+	showToast(fmt.Sprintf("%s deleted", entry.NameLoc), "Undo", func() {
+		if err := trash.Restore(trashed, entry.Path); err != nil {
+			log.Printf("Couldn't restore %s: %s", entry.Path, err)
+			statusLabel.SetText(fmt.Sprintf("Couldn't restore %s: %s", entry.NameLoc, err))
+			return
+		}
+		rescan()
+	})
+}
+
+// Scans desktop files again and refreshes the grid
+func rescan() {
+	status = parseDesktopFiles()
+	statusLabel.SetText(status)
+	setUpAppsFlowBox(phrase)
+	focusFirstItem()
+}
@@ -0,0 +1,88 @@
+package ui
+
+import (
+	"context"
+	"log"
+	"sync"
+
+	"github.com/ftphikari/wlaunchpad/internal/compositor"
+)
+
+var (
+	// whether the desktop was last asked to be revealed, on the main thread
+	desktopRevealed bool
+	// reveal or restore, carried out in order by one goroutine, IPC may be
+	// slow
+	desktopRequests chan bool
+	// the workspace to go back to, "" while the desktop isn't revealed
+	desktopMu         sync.Mutex
+	previousWorkspace string
+)
+
+// Reports whether the desktop is revealed whenever the window is shown:
+//
+//	[desktop]
+//	reveal = true
+func revealOnShow() bool {
+	return cfg.Bool("desktop", "reveal", false)
+}
+
+// Reveals the desktop behind the window, or brings the windows back
+func revealDesktop(reveal bool) {
+	if reveal == desktopRevealed {
+		return
+	}
+	if reveal && !compositor.CanShowDesktop() {
+		statusLabel.SetText("Only sway and Hyprland can show the desktop")
+		return
+	}
+	desktopRevealed = reveal
+	if desktopRequests == nil {
+		desktopRequests = make(chan bool, 16)
+		go func() {
+			for reveal := range desktopRequests {
+				setDesktopRevealed(reveal)
+			}
+		}()
+	}
+	desktopRequests <- reveal
+}
+
+func toggleDesktop() {
+	revealDesktop(!desktopRevealed)
+}
+
+// Switches workspaces as asked, unless already done. Blocks on the IPC.
+func setDesktopRevealed(reveal bool) {
+	desktopMu.Lock()
+	defer desktopMu.Unlock()
+	if reveal == (previousWorkspace != "") {
+		return
+	}
+	if reveal {
+		err := withRetries(func(ctx context.Context) error {
+			var err error
+			previousWorkspace, err = compositor.ShowDesktop(ctx)
+			return err
+		})
+		if err != nil {
+			log.Printf("Couldn't show the desktop: %s", err)
+		}
+		return
+	}
+	err := withRetries(func(ctx context.Context) error {
+		return compositor.RestoreDesktop(ctx, previousWorkspace)
+	})
+	if err != nil {
+		log.Printf("Couldn't switch back to workspace %s: %s", previousWorkspace, err)
+	}
+	previousWorkspace = ""
+}
+
+// Brings the windows back before quitting, waiting for it
+func restoreDesktopNow() {
+	if desktopRevealed {
+		desktopRevealed = false
+		setDesktopRevealed(false)
+	}
+}
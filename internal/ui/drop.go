@@ -0,0 +1,121 @@
+package ui
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/gotk3/gotk3/gdk"
+	"github.com/gotk3/gotk3/gtk"
+
+	"github.com/ftphikari/wlaunchpad/internal/entries"
+)
+
+// Lets desktop files be dropped on the window, to install them
+func setUpDrop() {
+	target, _ := gtk.TargetEntryNew("text/uri-list", 0, 0)
+	win.DragDestSet(gtk.DEST_DEFAULT_ALL, []gtk.TargetEntry{*target}, gdk.ACTION_COPY)
+	win.Connect("drag-data-received", func(window *gtk.Window, context *gdk.DragContext, x, y int, data *gtk.SelectionData, info, time uint) {
+		path := droppedDesktopFile(data.GetURIs())
+		if path == "" {
+			statusLabel.SetText("Only .desktop files can be dropped here")
+			return
+		}
+		entry, err := entries.ParseFile(filepath.Base(path), path)
+		if err == nil {
+			err = entries.Validate(entry)
+		}
+		if err != nil {
+			log.Printf("Couldn't install %s: %s", path, err)
+			statusLabel.SetText(fmt.Sprintf("Couldn't install %s: %s", filepath.Base(path), err))
+			return
+		}
+		confirmInstall(entry)
+	})
+}
+
+// Returns the path of the first local desktop file of the dropped URIs
+func droppedDesktopFile(uris []string) string {
+	for _, uri := range uris {
+		u, err := url.Parse(strings.TrimSpace(uri))
+		if err != nil || u.Scheme != "file" {
+			continue
+		}
+		if strings.HasSuffix(u.Path, ".desktop") {
+			return u.Path
+		}
+	}
+	return ""
+}
+
+// Shows what the entry would run before installing it, dropped files may come
+// from anywhere. Cancel has the focus, as in confirmLaunch.
+func confirmInstall(entry entries.DesktopEntry) {
+	popover, _ := gtk.PopoverNew(searchEntry)
+	popover.SetPosition(gtk.POS_BOTTOM)
+
+	box, _ := gtk.BoxNew(gtk.ORIENTATION_VERTICAL, 6)
+	box.SetMarginStart(10)
+	box.SetMarginEnd(10)
+	box.SetMarginTop(10)
+	box.SetMarginBottom(10)
+	popover.Add(box)
+
+	question, _ := gtk.LabelNew(fmt.Sprintf("Install %s?", entry.NameLoc))
+	box.PackStart(question, false, false, 0)
+	command, _ := gtk.LabelNew(entry.Exec)
+	command.SetLineWrap(true)
+	command.SetMaxWidthChars(40)
+	command.SetSelectable(true)
+	ctx, _ := command.GetStyleContext()
+	ctx.AddClass("dim-label")
+	box.PackStart(command, false, false, 0)
+
+	buttons, _ := gtk.BoxNew(gtk.ORIENTATION_HORIZONTAL, 6)
+	buttons.SetHomogeneous(true)
+	box.PackStart(buttons, false, false, 0)
+
+	cancel, _ := gtk.ButtonNewWithLabel("Cancel")
+	cancel.Connect("clicked", func() {
+		popover.Popdown()
+	})
+	buttons.PackStart(cancel, true, true, 0)
+
+	install, _ := gtk.ButtonNewWithLabel("Install")
+	ctx, _ = install.GetStyleContext()
+	ctx.AddClass("suggested-action")
+	install.Connect("clicked", func() {
+		popover.Popdown()
+		installEntry(entry)
+	})
+	buttons.PackStart(install, true, true, 0)
+
+	popover.Connect("closed", func() {
+		popover.Destroy()
+	})
+	box.ShowAll()
+	popover.Popup()
+	cancel.GrabFocus()
+}
+
+// Copies the desktop file into the user's applications directory, marks it
+// trusted and shows it in the grid
+func installEntry(entry entries.DesktopEntry) {
+	dest, err := entries.Install(entry.Path, entries.UserAppDir())
+	if err != nil {
+		log.Printf("Couldn't install %s: %s", entry.Path, err)
+		statusLabel.SetText(fmt.Sprintf("Couldn't install %s: %s", entry.NameLoc, err))
+		return
+	}
+	log.Printf("Installed %s as %s\n", entry.Path, dest)
+	// GNOME's file manager and desktop icons want this on top of the
+	// executable bit, others don't care
+	if err := exec.Command("gio", "set", dest, "metadata::trusted", "true").Run(); err != nil {
+		log.Printf("Couldn't mark %s trusted: %s", dest, err)
+	}
+	rescan()
+	showToast(fmt.Sprintf("%s installed", entry.NameLoc), "", nil)
+}
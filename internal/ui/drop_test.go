@@ -0,0 +1,19 @@
+package ui
+
+import "testing"
+
+func TestDroppedDesktopFile(t *testing.T) {
+	for _, tc := range []struct {
+		uris []string
+		want string
+	}{
+		{[]string{"file:///home/me/Downloads/My%20Tool.desktop\r\n"}, "/home/me/Downloads/My Tool.desktop"},
+		{[]string{"file:///home/me/notes.txt", "file:///tmp/tool.desktop"}, "/tmp/tool.desktop"},
+		{[]string{"https://example.com/tool.desktop"}, ""},
+		{nil, ""},
+	} {
+		if got := droppedDesktopFile(tc.uris); got != tc.want {
+			t.Errorf("%q: expected %q, got %q", tc.uris, tc.want, got)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package ui
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/gotk3/gotk3/gdk"
+	"github.com/gotk3/gotk3/gtk"
+)
+
+// An editing key of the search, pressed with Ctrl
+type editingKey struct {
+	key         uint
+	label       string
+	description string
+	// handles the key with the text of the search, reports whether it was
+	// taken
+	run func(text string) bool
+}
+
+// Handled before the window's own keys. Off an empty search Ctrl and a letter
+// are left to jumpToSection, Ctrl+V pastes there only when the grid has no
+// sections.
+var editingKeys = []editingKey{
+	{gdk.KEY_v, "Ctrl+V", "Paste into the search", pasteClipboard},
+	{gdk.KEY_a, "Ctrl+A", "Select all of the search", selectSearch},
+	{gdk.KEY_w, "Ctrl+W", "Delete the word before the cursor, as in terminals", deleteWord},
+}
+
+// Runs the editing key pressed, reports whether it was taken
+func handleEditingKey(key *gdk.EventKey) bool {
+	if gdk.ModifierType(key.State())&bindingMods != gdk.CONTROL_MASK {
+		return false
+	}
+	text, _ := searchEntry.GetText()
+	for _, k := range editingKeys {
+		if gdk.KeyvalToLower(key.KeyVal()) == k.key {
+			return k.run(text)
+		}
+	}
+	return false
+}
+
+func pasteClipboard(text string) bool {
+	if text == "" && !searchEntry.IsFocus() && letterSections != nil {
+		return false
+	}
+	if !searchEntry.IsFocus() {
+		searchEntry.GrabFocusWithoutSelecting()
+	}
+	searchEntry.PasteClipboard()
+	return true
+}
+
+func selectSearch(text string) bool {
+	if text == "" || !searchEntry.IsFocus() {
+		return false
+	}
+	searchEntry.SelectRegion(0, -1)
+	return true
+}
+
+func deleteWord(text string) bool {
+	if text == "" || !searchEntry.IsFocus() {
+		return false
+	}
+	if _, _, selected := searchEntry.GetSelectionBounds(); selected {
+		searchEntry.DeleteSelection()
+		return true
+	}
+	end := searchEntry.GetPosition()
+	searchEntry.DeleteText(wordStart([]rune(text), end), end)
+	return true
+}
+
+// Returns the position of the start of the word before pos, skipping the
+// spaces after it
+func wordStart(text []rune, pos int) int {
+	if pos > len(text) {
+		pos = len(text)
+	}
+	for pos > 0 && unicode.IsSpace(text[pos-1]) {
+		pos--
+	}
+	for pos > 0 && !unicode.IsSpace(text[pos-1]) {
+		pos--
+	}
+	return pos
+}
+
+// Middle clicks anywhere but on the search paste the primary selection into
+// it; the search entry pastes it itself
+func pastePrimary() {
+	clipboard, err := gtk.ClipboardGet(gdk.SELECTION_PRIMARY)
+	if err != nil {
+		return
+	}
+	text, err := clipboard.WaitForText()
+	if err != nil || strings.TrimSpace(text) == "" {
+		return
+	}
+	searchEntry.GrabFocusWithoutSelecting()
+	// a phrase is one line
+	pos := searchEntry.InsertText(strings.Join(strings.Fields(text), " "), searchEntry.GetPosition())
+	searchEntry.SetPosition(pos)
+}
@@ -0,0 +1,22 @@
+package ui
+
+import "testing"
+
+func TestWordStart(t *testing.T) {
+	for _, tc := range []struct {
+		text string
+		pos  int
+		want int
+	}{
+		{"fire", 4, 0},
+		{"web browser", 11, 4},
+		{"web browser  ", 13, 4}, // spaces before the cursor go too
+		{"web browser", 6, 4},
+		{"épée long", 4, 0}, // positions count characters
+		{"", 0, 0},
+	} {
+		if got := wordStart([]rune(tc.text), tc.pos); got != tc.want {
+			t.Errorf("%q at %d: expected %d, got %d", tc.text, tc.pos, tc.want, got)
+		}
+	}
+}
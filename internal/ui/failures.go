@@ -0,0 +1,67 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os/exec"
+	"time"
+
+	"github.com/gotk3/gotk3/glib"
+	"github.com/gotk3/gotk3/gtk"
+
+	"github.com/ftphikari/wlaunchpad/internal/entries"
+)
+
+// Time a launched app has to fail in to be reported. Apps exiting with an
+// error later did run, and likely told why themselves.
+const quickExit = 2 * time.Second
+
+// Hides the window and waits for the launched app a moment, reporting it if it
+// exits with an error right away. The window is gone by then, so the report is
+// a desktop notification. Without the daemon the launcher stays around for
+// that moment, hidden.
+func watchLaunch(entry entries.DesktopEntry, cmd *exec.Cmd) {
+	win.Hide()
+	exited := make(chan error, 1)
+	// waiting also reaps the app once it exits, whenever that is
+	go func() {
+		exited <- cmd.Wait()
+	}()
+	go func() {
+		select {
+		case err := <-exited:
+			if err != nil {
+				notifyLaunchFailure(entry, err)
+			}
+		case <-time.After(quickExit):
+		}
+		if !settings.Daemon {
+			glib.IdleAdd(gtk.MainQuit)
+		}
+	}()
+}
+
+// Reports an app which couldn't be started, in the toast while the window is
+// still there, otherwise in a desktop notification
+func reportLaunchFailure(entry entries.DesktopEntry, err error) {
+	log.Printf("Couldn't launch %s: %s", entry.DesktopID, err)
+	if win.GetVisible() {
+		showToast(fmt.Sprintf("Couldn't launch %s: %s", entry.NameLoc, err), "", nil)
+		return
+	}
+	go notifyLaunchFailure(entry, err)
+}
+
+func notifyLaunchFailure(entry entries.DesktopEntry, err error) {
+	body := err.Error()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		body = fmt.Sprintf("%s exited right away: %s", entry.Exec, exitErr)
+	}
+	log.Printf("Launching %s failed: %s", entry.DesktopID, body)
+	if err := exec.Command("notify-send", "-a", "wlaunchpad", "-u", "critical", "-i", entry.Icon,
+		fmt.Sprintf("Couldn't launch %s", entry.NameLoc), body).Run(); err != nil {
+		log.Printf("Couldn't send a notification: %s", err)
+	}
+}
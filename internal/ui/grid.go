@@ -0,0 +1,331 @@
+package ui
+
+import (
+	"fmt"
+	"log"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/gotk3/gotk3/gtk"
+
+	"github.com/ftphikari/wlaunchpad/internal/config"
+	"github.com/ftphikari/wlaunchpad/internal/entries"
+	"github.com/ftphikari/wlaunchpad/internal/ipc"
+	"github.com/ftphikari/wlaunchpad/internal/launch"
+)
+
+func setUpAppsFlowBox(searchPhrase string) {
+	if appFlowBox != nil {
+		releaseAppButtons()
+	} else {
+		appFlowBox, _ = gtk.FlowBoxNew()
+		appFlowBox.SetMinChildrenPerLine(columns)
+		appFlowBox.SetMaxChildrenPerLine(columns)
+		appFlowBox.SetColumnSpacing(settings.Spacing)
+		appFlowBox.SetRowSpacing(settings.Spacing)
+		appFlowBox.SetHomogeneous(true)
+		appFlowBox.SetSelectionMode(gtk.SELECTION_NONE)
+		// scroll to the focused button, when moving the focus ourselves too
+		appFlowBox.SetFocusVAdjustment(resultWindow.GetVAdjustment())
+	}
+	if scanning {
+		setUpSkeleton()
+		resultWindow.ShowAll()
+		return
+	}
+
+	// the same snapshot for the whole pass, even if a rescan swaps in another
+	snapshot := currentEntries()
+	// "ss region" searches the provider with the "ss " prefix for "region"
+	prefixed, query := prefixedProvider(searchPhrase)
+	phrases := searchPhrases(query)
+	scope.Fuzzy = fuzzySearch()
+	matching := func(entry entries.DesktopEntry) bool {
+		return providerShown(entry, prefixed) &&
+			(categoryFilter == "" || entry.InCategory(categoryFilter)) &&
+			(query == "" || matches(entry, phrases))
+	}
+	shows := func(entry entries.DesktopEntry) bool {
+		return !entry.NoDisplay && matching(entry)
+	}
+	setUpPinned(snapshot, shows)
+	setUpSuggested(snapshot, searchPhrase)
+	setUpRecent(snapshot, searchPhrase)
+
+	// entries matching an alias come first
+	var aliased []entries.DesktopEntry
+	if prefixed == "" {
+		aliased = aliasedEntries(snapshot, searchPhrase)
+	}
+	for _, entry := range aliased {
+		if categoryFilter == "" || entry.InCategory(categoryFilter) {
+			appFlowBox.Add(getAppButton(entry))
+		}
+	}
+
+	var shown []entries.DesktopEntry
+	for _, entry := range snapshot.Entries() {
+		if !containsEntry(aliased, entry.DesktopID) && shows(entry) {
+			shown = append(shown, entry)
+		}
+	}
+	if len(shown) == 0 && len(aliased) == 0 && query != "" && hiddenFallback() {
+		for _, entry := range snapshot.Entries() {
+			if entry.NoDisplay && matching(entry) {
+				shown = append(shown, entry)
+			}
+		}
+	}
+	sorter := gridSorter(query, phrases)
+	sorter.Sort(shown)
+	shown = arrangeByProvider(shown, providerPosition, providerLimit)
+	letterSections, sectionsStart = nil, len(gridButtons)
+	if _, ok := sorter.(entries.Alphabetical); ok && searchPhrase == "" && categoryFilter == "" {
+		letterSections = entries.Sections(shown)
+	}
+	for _, entry := range shown {
+		appFlowBox.Add(getAppButton(entry))
+	}
+	// While moving focus with arrow keys we want buttons to get focus directly
+	appFlowBox.GetChildren().Foreach(func(item interface{}) {
+		item.(*gtk.Widget).SetCanFocus(false)
+	})
+	resultWindow.ShowAll()
+	logGridStats()
+}
+
+func matches(entry entries.DesktopEntry, phrases []string) bool {
+	_, ok := scope.Find(entry, phrases)
+	return ok
+}
+
+// Whether words of the search may be found apart, and by their letters in
+// order in names and keywords, when the search isn't found as typed:
+//
+//	[search]
+//	fuzzy = false # on by default
+func fuzzySearch() bool {
+	return cfg.Bool("search", "fuzzy", true)
+}
+
+// Whether a search matching nothing else lists the hidden entries it matches,
+// like settings panels shipped with NoDisplay=true, rather than nothing:
+//
+//	[search]
+//	hidden-fallback = false # on by default
+func hiddenFallback() bool {
+	return cfg.Bool("search", "hidden-fallback", true)
+}
+
+// Returns the phrase and its versions retyped from the keyboard layouts listed
+// in the config file:
+//
+//	[search]
+//	layouts = ["ru", "ua"]
+func searchPhrases(searchPhrase string) []string {
+	phrases := []string{searchPhrase}
+	for _, layout := range cfg.List("search", "layouts") {
+		if retyped, ok := entries.Retype(searchPhrase, layout); ok {
+			phrases = append(phrases, retyped)
+		}
+	}
+	return phrases
+}
+
+// Shows only entries of a category, set by show requests
+var categoryFilter string
+
+// Filters the grid by category, "" shows all entries again
+func setCategoryFilter(category string) {
+	categoryFilter = category
+	if category != "" {
+		statusLabel.SetText(fmt.Sprintf("%s only, Escape shows all", category))
+	} else {
+		statusLabel.SetText(status)
+	}
+	setUpAppsFlowBox(phrase)
+	focusFirstItem()
+}
+
+func showWindow() {
+	wake()
+	categoryFilter = ""
+	if settings.Badges {
+		badgeCounts = ipc.NotificationCounts()
+	}
+	status = parseDesktopFiles()
+	statusLabel.SetText(status)
+	pruneIconCache()
+	loadExternalScores()
+	checkForUpdates()
+	searchEntry.SetText("")
+	setUpAppsFlowBox("")
+	if _, _, ok := adaptiveIconSizes(); ok && windowWidth > 0 {
+		// apps may have been installed or removed since
+		relayout(windowWidth, windowHeight)
+	}
+	resultWindow.GetVAdjustment().SetValue(0)
+	focusOnShow()
+	win.ShowAll()
+}
+
+func focusFirstItem() {
+	// pinned, suggested and recent entries come first
+	if len(gridButtons) > 0 {
+		gridButtons[0].GrabFocus()
+	}
+}
+
+// Scans desktop files and adds our synthetic entries, returns the summary for
+// the status line
+func parseDesktopFiles() string {
+	return storeEntries(scanDesktopFiles())
+}
+
+// Scans desktop files, leaving out the ones not to show. Safe to call from any
+// goroutine, it doesn't touch GTK.
+func scanDesktopFiles() (list []entries.DesktopEntry, invalid int, slow []string) {
+	scanned, slow := entries.Scan()
+	list, dropped := entries.Valid(scanned)
+	var warnings []ipc.Invalid
+	for _, entry := range scanned {
+		if err, ok := dropped[entry.DesktopID]; ok {
+			log.Printf("Dropped %s: %s", entry.DesktopID, err)
+			warnings = append(warnings, ipc.Invalid{ID: entry.DesktopID, Path: entry.Path, Reason: err.Error()})
+		}
+	}
+	log.Printf("Dropped %v invalid desktop files\n", len(dropped))
+	scanWarnings.Store(ipc.NewWarnings(warnings, slow))
+	if !settings.NoTryExec {
+		var missing int
+		list, missing = entries.InstalledOnly(list)
+		log.Printf("Dropped %v entries with their TryExec program missing\n", missing)
+	}
+	if !settings.AllDesktops {
+		var other int
+		list, other = entries.ShownInDesktops(list, entries.CurrentDesktops())
+		log.Printf("Dropped %v entries meant for other desktops than %q\n", other, entries.CurrentDesktops())
+	}
+	return list, len(dropped), slow
+}
+
+// Adds our synthetic entries to the scanned ones and swaps them in, returns
+// the summary for the status line
+func storeEntries(list []entries.DesktopEntry, invalid int, slow []string) string {
+	list, excluded := entries.Filter(list, entryPatterns("include"), entryPatterns("exclude"))
+	log.Printf("Dropped %v excluded entries\n", excluded)
+	checkNewApps(list)
+	loadIconOverrides()
+	list = loadOverrides().Apply(list)
+	list = entries.Merge(list, mergeGroups(), cfg.Bool("merge", "same-binary", false))
+	list = providerEntries(list)
+
+	snapshot := entries.NewSnapshot(list)
+	model.Store(snapshot)
+	summary := fmt.Sprintf("%v entries (+%v hidden)", len(snapshot.Entries())-snapshot.Hidden(), snapshot.Hidden())
+	if invalid > 0 {
+		summary += fmt.Sprintf(", %v invalid skipped", invalid)
+	}
+	if len(slow) > 0 {
+		summary += fmt.Sprintf(" — %s too slow, skipped", strings.Join(slow, ", "))
+	}
+	// with dunst, which doesn't tell the apps apart
+	if n := badgeCounts[ipc.AllApps]; n > 0 {
+		summary += fmt.Sprintf(" — %d notifications", n)
+	}
+	return summary
+}
+
+// Patterns of the entries to list, or to leave out, matched against desktop
+// IDs, Exec lines and programs, and categories:
+//
+//	[entries]
+//	exclude = ["avahi-discover", "bssh", "bvnc"]
+//	include = ["Game", "org.gnome.*"] # only these, all if empty
+func entryPatterns(key string) []string {
+	if patterns := cfg.List("entries", key); patterns != nil {
+		return patterns
+	}
+	if pattern := cfg.Str("entries", key, ""); pattern != "" {
+		return []string{pattern}
+	}
+	return nil
+}
+
+func overridesPath() string {
+	return filepath.Join(config.Dir(), "overrides.toml")
+}
+
+// Renames, icons, commands and entries to hide given in overrides.toml next
+// to the config file
+func loadOverrides() entries.Overrides {
+	overrides, err := entries.LoadOverrides(overridesPath())
+	if err != nil {
+		log.Printf("Couldn't read %s: %s", overridesPath(), err)
+	}
+	return overrides
+}
+
+func launchEntry(entry entries.DesktopEntry) {
+	if entry.Action != nil {
+		recordLaunch(entry)
+		entry.Action()
+		return
+	}
+	cmd, err := startCommand(entry, activationToken(entry))
+	if err != nil {
+		// the window stays, showing why nothing happened
+		reportLaunchFailure(entry, err)
+		return
+	}
+	if cmd == nil {
+		closeWindow()
+		return
+	}
+	// only launches which happened count for suggestions
+	recordLaunch(entry)
+	if settings.Daemon && wayland() {
+		detectXWayland(entry.DesktopID, cmd.Process.Pid)
+	}
+	watchLaunch(entry, cmd)
+}
+
+// Hides the window in daemon mode, quits otherwise
+func closeWindow() {
+	if settings.Daemon {
+		win.Hide()
+	} else {
+		gtk.MainQuit()
+	}
+}
+
+// Graphics cards, for entries preferring the non-default GPU
+var gpus launch.GPUs
+
+// Starts the Exec line of the entry, handing it the activation token, returns
+// the started command, nil in dry run mode
+func startCommand(entry entries.DesktopEntry, token string) (*exec.Cmd, error) {
+	cmd := launch.Command(entry.Exec, launch.Entry{Name: entry.NameLoc, Icon: entry.Icon, Path: entry.Path, Dir: entry.WorkingDir}, entry.Terminal, settings.Term)
+	launch.SetActivationToken(cmd, token)
+	if entry.PrefersNonDefaultGPU {
+		launch.UseNonDefaultGPU(cmd, gpus)
+	}
+	if settings.DryRun {
+		fmt.Print(launch.Describe(cmd))
+		return nil, nil
+	}
+	if settings.Debug {
+		// for apps failing only when not started from a terminal
+		dir := filepath.Join(config.CacheDir(), "logs")
+		if f, err := launch.LogOutput(cmd, dir, strings.TrimSuffix(entry.DesktopID, ".desktop")); err != nil {
+			log.Printf("Couldn't log the output of %s: %s", entry.DesktopID, err)
+		} else {
+			defer f.Close()
+		}
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	return cmd, nil
+}
@@ -0,0 +1,68 @@
+package ui
+
+import (
+	"log"
+	"runtime/debug"
+	"time"
+
+	"github.com/gotk3/gotk3/glib"
+)
+
+var (
+	// the window is destroyed, until shown again
+	hibernating    bool
+	hibernateTimer glib.SourceHandle
+)
+
+// Hibernates after the window stayed hidden for -hibernate, in daemon mode
+func scheduleHibernation() {
+	if !settings.Daemon || settings.Hibernate <= 0 || hibernating {
+		return
+	}
+	cancelHibernation()
+	hibernateTimer = glib.TimeoutAdd(uint(settings.Hibernate/time.Millisecond), func() bool {
+		hibernateTimer = 0
+		hibernate()
+		return false
+	})
+}
+
+func cancelHibernation() {
+	if hibernateTimer != 0 {
+		glib.SourceRemove(hibernateTimer)
+		hibernateTimer = 0
+	}
+}
+
+// Destroys the window, the buttons kept for reuse and the cached icons. The
+// parsed entries, history and watchers stay, so that waking up only has to
+// create widgets again.
+func hibernate() {
+	if win.GetVisible() {
+		return
+	}
+	log.Printf("Hidden for %v, hibernating\n", settings.Hibernate)
+	logGridStats()
+	hibernating = true
+	win.Destroy()
+	for _, ab := range freeButtons {
+		ab.Destroy()
+	}
+	freeButtons, gridButtons, pendingIcons = nil, nil, nil
+	appFlowBox, suggestedFlowBox, recentFlowBox, pinnedFlowBox = nil, nil, nil, nil
+	pinnedRow = nil
+	clearIconCache()
+	// GTK objects are freed once their Go wrappers are collected
+	debug.FreeOSMemory()
+}
+
+// Creates the window again if hibernating, before showing it
+func wake() {
+	if !hibernating {
+		return
+	}
+	start := time.Now()
+	hibernating = false
+	buildWindow()
+	log.Printf("Woke up in %v ms\n", time.Since(start).Milliseconds())
+}
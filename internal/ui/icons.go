@@ -0,0 +1,99 @@
+package ui
+
+import (
+	"log"
+	"runtime"
+	"strings"
+
+	"github.com/gotk3/gotk3/gdk"
+	"github.com/gotk3/gotk3/gtk"
+)
+
+func createPixbuf(icon string, size int) (*gdk.Pixbuf, error) {
+	if strings.Contains(icon, "/") {
+		pixbuf, err := gdk.PixbufNewFromFileAtSize(icon, size, size)
+		if err != nil {
+			log.Printf("%s", err)
+			return nil, err
+		}
+		return pixbuf, nil
+	} else if strings.HasSuffix(icon, ".svg") || strings.HasSuffix(icon, ".png") || strings.HasSuffix(icon, ".xpm") {
+		// for entries like "Icon=netflix-desktop.svg"
+		icon = strings.Split(icon, ".")[0]
+	}
+
+	pixbuf, err := iconTheme.LoadIcon(icon, size, gtk.ICON_LOOKUP_FORCE_SIZE)
+	if err != nil {
+		if strings.HasPrefix(icon, "/") {
+			pixbuf, err := gdk.PixbufNewFromFileAtSize(icon, size, size)
+			if err != nil {
+				return nil, err
+			}
+			return pixbuf, nil
+		}
+
+		pixbuf, err := iconTheme.LoadIcon(icon, size, gtk.ICON_LOOKUP_FORCE_SIZE)
+		if err != nil {
+			return nil, err
+		}
+		return pixbuf, nil
+	}
+	return pixbuf, nil
+}
+
+func iconPixbuf(icon string) *gdk.Pixbuf {
+	pixbuf, ok := iconCache[icon]
+	if ok {
+		return pixbuf
+	}
+
+	var err error
+	if icon != "" {
+		pixbuf, err = createPixbuf(icon, iconSize)
+		if err != nil {
+			log.Print(err)
+			iconErrors[icon] = err
+			pixbuf, err = createPixbuf("image-missing", iconSize)
+		}
+	}
+	if err != nil {
+		log.Print(err)
+		pixbuf, _ = createPixbuf("unknown", iconSize)
+	}
+	iconCache[icon] = pixbuf
+	livePixbufs++
+	return pixbuf
+}
+
+// Drops cached pixbufs of icons no entry uses anymore (e.g. uninstalled apps).
+// We unref them right away instead of waiting for the Go finalizer; images
+// still showing them hold their own reference.
+func pruneIconCache() {
+	used := make(map[string]bool)
+	for _, entry := range currentEntries().Entries() {
+		used[entryIcon(entry)] = true
+	}
+	for icon := range iconCache {
+		if !used[icon] {
+			dropIcon(icon)
+		}
+	}
+}
+
+// Drops all cached pixbufs, e.g. when the icon size changes
+func clearIconCache() {
+	for icon := range iconCache {
+		dropIcon(icon)
+	}
+}
+
+func dropIcon(icon string) {
+	pixbuf := iconCache[icon]
+	delete(iconCache, icon)
+	delete(iconErrors, icon)
+	livePixbufs--
+	if pixbuf != nil {
+		runtime.SetFinalizer(pixbuf.Object, nil)
+		pixbuf.Unref()
+	}
+}
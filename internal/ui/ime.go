@@ -0,0 +1,44 @@
+package ui
+
+import (
+	"log"
+
+	"github.com/gotk3/gotk3/gtk"
+)
+
+// Whether an input method is composing text in the search entry, keys like
+// Escape are its to handle then
+var composing bool
+
+// Sets up input methods for the search entry, configured in the config file:
+//
+//	[ime]
+//	module = "wayland" # GTK input method module, "wayland" speaks text-input-v3
+//	reserve = 160      # room kept below the entry for the candidate popup
+//
+// Returns the box making room for the candidate popup while composing, to be
+// packed below the search entry.
+func setUpIME() *gtk.Box {
+	if module := cfg.Str("ime", "module", ""); module != "" {
+		if err := searchEntry.SetProperty("im-module", module); err != nil {
+			log.Printf("Couldn't set input method module %q: %s", module, err)
+		}
+	}
+
+	reserve, _ := gtk.BoxNew(gtk.ORIENTATION_VERTICAL, 0)
+	reserve.SetNoShowAll(true)
+	height := cfg.Int("ime", "reserve", 0)
+	reserve.SetSizeRequest(-1, height)
+
+	searchEntry.Connect("preedit-changed", func(entry *gtk.SearchEntry, preedit string) {
+		composing = preedit != ""
+		// the popup of a layer surface spanning the output would cover the
+		// grid, so the grid moves out of its way
+		if composing && height > 0 {
+			reserve.Show()
+		} else {
+			reserve.Hide()
+		}
+	})
+	return reserve
+}
@@ -0,0 +1,182 @@
+package ui
+
+import (
+	"log"
+	"strings"
+
+	"github.com/gotk3/gotk3/gdk"
+	"github.com/gotk3/gotk3/gtk"
+)
+
+// A key binding of the window. Keys can be remapped in the config file, by
+// action name, in the format of gtk_accelerator_parse:
+//
+//	[keys]
+//	help = ["F1", "question"]
+//	debug = "<Control>d"
+type binding struct {
+	name        string
+	accels      []string
+	description string
+	run         func()
+	// the focused widget gets the key too
+	passThrough bool
+
+	keys []accelKey
+}
+
+type accelKey struct {
+	key  uint
+	mods gdk.ModifierType
+}
+
+// Modifiers telling bindings apart. Shift is left out, it is part of the
+// keyval already: "question" is Shift+slash.
+const bindingMods = gdk.CONTROL_MASK | gdk.MOD1_MASK | gdk.SUPER_MASK
+
+var bindings []*binding
+
+func setUpBindings() {
+	bindings = []*binding{
+		{name: "back", accels: []string{"Escape"}, description: "Clear the search, show all categories or close", run: back, passThrough: true},
+		{name: "help", accels: []string{"F1", "question"}, description: "Show this cheatsheet", run: toggleCheatsheet},
+		{name: "debug", accels: []string{"F12"}, description: "Show why entries match the search", run: toggleMatchDebug},
+		{name: "zoom-in", accels: []string{"<Control>plus", "<Control>equal", "<Control>KP_Add"}, description: "Zoom in", run: func() { setZoom(zoom + zoomStep) }},
+		{name: "zoom-out", accels: []string{"<Control>minus", "<Control>KP_Subtract"}, description: "Zoom out", run: func() { setZoom(zoom - zoomStep) }},
+		{name: "desktop", accels: []string{"<Alt>d"}, description: "Show the desktop behind the launcher, or the windows again", run: toggleDesktop},
+		{name: "zoom-reset", accels: []string{"<Control>0", "<Control>KP_0"}, description: "Reset the zoom", run: func() { setZoom(1) }},
+	}
+
+	for _, b := range bindings {
+		if accels := cfg.List("keys", b.name); accels != nil {
+			b.accels = accels
+		} else if cfg.Has("keys", b.name) {
+			b.accels = []string{cfg.Str("keys", b.name, "")}
+		}
+		for _, accel := range b.accels {
+			key, mods := gtk.AcceleratorParse(accel)
+			if key == 0 {
+				log.Printf("Invalid key %q for %s", accel, b.name)
+				continue
+			}
+			b.keys = append(b.keys, accelKey{gdk.KeyvalToLower(key), mods & bindingMods})
+		}
+	}
+}
+
+// A key of the window handled after the bindings and the editing keys of the
+// search, by the launcher or left to the widgets. Not remappable.
+type windowKey struct {
+	label       string
+	description string
+	// whether the cheatsheet lists it, nil for always
+	shown func() bool
+	// reports whether the key is this one, and whether it was taken
+	handle func(key *gdk.EventKey) (matched, taken bool)
+}
+
+var windowKeys = []windowKey{
+	{label: "Arrows", description: "Move in the grid, wrapping around at the edges", handle: func(key *gdk.EventKey) (bool, bool) {
+		dx, dy := arrowDirection(key.KeyVal())
+		if dx == 0 && dy == 0 {
+			return false, false
+		}
+		// GTK's focus chain skips cells once the grid is filtered
+		if focusedButton() == -1 || gdk.ModifierType(key.State())&bindingMods != 0 {
+			return true, false
+		}
+		moveFocus(dx, dy)
+		return true, true
+	}},
+	{label: "Tab", description: "Move between entries", handle: widgetKeys(gdk.KEY_Tab)},
+	{label: "Enter", description: "Launch the focused entry", handle: widgetKeys(gdk.KEY_Return)},
+	{label: "Page Up, Page Down, Home, End", description: "Scroll the grid",
+		handle: widgetKeys(gdk.KEY_Page_Up, gdk.KEY_Page_Down, gdk.KEY_Home, gdk.KEY_End)},
+	// Escape is a binding the widgets get too
+	{handle: widgetKeys(gdk.KEY_Escape, gdk.KEY_downarrow)},
+	{label: "Ctrl+Letter", description: "Jump to the entries starting with it", handle: func(key *gdk.EventKey) (bool, bool) {
+		if gdk.ModifierType(key.State())&bindingMods == gdk.CONTROL_MASK && jumpToSection(gdk.KeyvalToUnicode(key.KeyVal())) {
+			return true, true
+		}
+		return false, false
+	}},
+	{label: "Letters, /", description: "Jump to the next entry starting with it, or to the search", shown: jumpByLetter,
+		handle: func(key *gdk.EventKey) (bool, bool) {
+			if !searchEntry.IsFocus() && key.State()&gdk.CONTROL_MASK == 0 && jumpToLetter(gdk.KeyvalToUnicode(key.KeyVal())) {
+				return true, true
+			}
+			return false, false
+		}},
+}
+
+// Keys GTK's widgets handle themselves
+func widgetKeys(keys ...uint) func(key *gdk.EventKey) (bool, bool) {
+	return func(key *gdk.EventKey) (bool, bool) {
+		for _, k := range keys {
+			if key.KeyVal() == k {
+				return true, false
+			}
+		}
+		return false, false
+	}
+}
+
+// Runs the window key pressed, reports whether it was taken. Other keys go to
+// the search.
+func handleWindowKey(key *gdk.EventKey) bool {
+	for _, k := range windowKeys {
+		if matched, taken := k.handle(key); matched {
+			return taken
+		}
+	}
+	if !searchEntry.IsFocus() {
+		searchEntry.GrabFocusWithoutSelecting()
+	}
+	return false
+}
+
+// Runs the binding of the key, reports whether the key should be handled no
+// further
+func handleBinding(event *gdk.EventKey) bool {
+	pressed := accelKey{gdk.KeyvalToLower(event.KeyVal()), gdk.ModifierType(event.State()) & bindingMods}
+	for _, b := range bindings {
+		for _, k := range b.keys {
+			if k != pressed {
+				continue
+			}
+			// plain characters are typed into the search, unless it is empty
+			if k.mods == 0 && gdk.KeyvalToUnicode(k.key) != 0 {
+				if s, _ := searchEntry.GetText(); s != "" {
+					return false
+				}
+			}
+			b.run()
+			return !b.passThrough
+		}
+	}
+	return false
+}
+
+// Returns the keys of a binding as shown to the user
+func (b *binding) labels() string {
+	var labels []string
+	for _, k := range b.keys {
+		labels = append(labels, gtk.AcceleratorGetLabel(k.key, k.mods))
+	}
+	return strings.Join(labels, ", ")
+}
+
+// Clears the search, then the category filter, then closes the window
+func back() {
+	s, _ := searchEntry.GetText()
+	if s != "" {
+		searchEntry.GrabFocus()
+		searchEntry.SetText("")
+	} else if categoryFilter != "" {
+		setCategoryFilter("")
+	} else if settings.Daemon {
+		win.Hide()
+	} else {
+		gtk.MainQuit()
+	}
+}
@@ -0,0 +1,48 @@
+package ui
+
+import (
+	"log"
+
+	"github.com/gotk3/gotk3/glib"
+	"github.com/gotk3/gotk3/gtk"
+
+	"github.com/ftphikari/wlaunchpad/internal/ipc"
+)
+
+// DesktopID -> last known state, only touched from the GTK main loop
+var launcherEntries = make(map[string]ipc.LauncherEntryState)
+
+func watchLauncherEntries() {
+	err := ipc.WatchLauncherEntries(func(id string, props map[string]string) {
+		glib.IdleAdd(func() bool {
+			applyLauncherEntryUpdate(id, props)
+			return false
+		})
+	})
+	if err != nil {
+		log.Printf("Couldn't watch LauncherEntry updates: %s", err)
+	}
+}
+
+// Refreshes the badge and progress bar of the entry's buttons on screen
+func applyLauncherEntryUpdate(id string, props map[string]string) {
+	state := launcherEntries[id]
+	state.Update(props)
+	launcherEntries[id] = state
+
+	for _, ab := range gridButtons {
+		if ab.entry.DesktopID == id {
+			ab.setBadge()
+			updateProgressBar(ab.progress, state)
+		}
+	}
+}
+
+func updateProgressBar(bar *gtk.ProgressBar, state ipc.LauncherEntryState) {
+	if state.ProgressVisible {
+		bar.SetFraction(state.Progress)
+		bar.Show()
+	} else {
+		bar.Hide()
+	}
+}
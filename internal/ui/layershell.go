@@ -0,0 +1,37 @@
+//go:build !nolayershell
+// +build !nolayershell
+
+package ui
+
+import (
+	"github.com/dlasky/gotk3-layershell/layershell"
+	"github.com/gotk3/gotk3/gdk"
+
+	"github.com/ftphikari/wlaunchpad/internal/config"
+)
+
+func init() {
+	config.AddFeature("window", "layer-shell")
+}
+
+// Makes the window an overlay covering the whole output
+func initLayerShell() {
+	layershell.InitForWindow(win)
+	layershell.SetAnchor(win, layershell.LAYER_SHELL_EDGE_BOTTOM, true)
+	layershell.SetAnchor(win, layershell.LAYER_SHELL_EDGE_TOP, true)
+	layershell.SetAnchor(win, layershell.LAYER_SHELL_EDGE_LEFT, true)
+	layershell.SetAnchor(win, layershell.LAYER_SHELL_EDGE_RIGHT, true)
+	layershell.SetLayer(win, layershell.LAYER_SHELL_LAYER_OVERLAY)
+	if settings.OSK {
+		// Respect exclusive zones of other surfaces, so an on-screen
+		// keyboard shrinks the window instead of covering the grid
+		layershell.SetExclusiveZone(win, 0)
+	} else {
+		layershell.SetExclusiveZone(win, -1)
+	}
+	layershell.SetKeyboardMode(win, layershell.LAYER_SHELL_KEYBOARD_MODE_EXCLUSIVE)
+}
+
+func setMonitor(monitor *gdk.Monitor) {
+	layershell.SetMonitor(win, monitor)
+}
@@ -0,0 +1,21 @@
+//go:build nolayershell
+// +build nolayershell
+
+package ui
+
+import (
+	"log"
+
+	"github.com/gotk3/gotk3/gdk"
+)
+
+// Without layer-shell the window is a plain fullscreen one, as on X11
+func initLayerShell() {
+	log.Println("Built without layer-shell support, using a fullscreen window")
+	win.SetDecorated(false)
+	win.Fullscreen()
+}
+
+func setMonitor(monitor *gdk.Monitor) {
+	log.Printf("Built without layer-shell support, can't move the window to %s", settings.TargetOutput)
+}
@@ -0,0 +1,257 @@
+package ui
+
+import (
+	"log"
+	"math"
+	"net/url"
+	"strconv"
+
+	"github.com/gotk3/gotk3/gtk"
+)
+
+// Layout in effect: the configured columns and icon size, or less when the
+// window is too narrow for them
+var (
+	columns  uint
+	iconSize int
+)
+
+const (
+	minIconSize  = 32
+	minCellWidth = 100 // room for the label, at the default text size
+	cellPadding  = 40  // button padding and margins around the icon
+	labelPadding = 16  // button padding around the label
+)
+
+// Height of a line of entry names at the default font size, the sizes here
+// are for it
+const defaultLineHeight = 18
+
+// Height of a line of entry names as rendered, see measureText
+var lineHeight = defaultLineHeight
+
+// Measures a line of an entry name as rendered: with the font, the DPI (set
+// from the text scaling factor by desktops), the user style and the zoom.
+// Reports whether it changed.
+func measureText() bool {
+	label := statusLabel
+	if len(gridButtons) > 0 {
+		label = gridButtons[0].label
+	}
+	if label == nil {
+		return false
+	}
+	_, height := label.GetPreferredHeight()
+	if height <= 0 || height == lineHeight {
+		return false
+	}
+	log.Printf("Text line height: %d\n", height)
+	lineHeight = height
+	return true
+}
+
+// Scales a size meant for the default text size to the rendered one
+func scaleToText(size int) int {
+	return size * lineHeight / defaultLineHeight
+}
+
+// Returns the width of a grid cell with icons of the size
+func cellWidth(size int) int {
+	minWidth := scaleToText(minCellWidth)
+	if cell := size + cellPadding; cell > minWidth {
+		return cell
+	}
+	return minWidth
+}
+
+// Returns the width entry names get ellipsized at
+func labelWidth(size int) int {
+	return cellWidth(size) - labelPadding
+}
+
+// Returns the number of columns and the icon size fitting the width. Icons
+// shrink first, down to half of the configured size, then columns are dropped.
+func fitGrid(width int, maxColumns uint, maxIconSize int, spacing uint) (uint, int) {
+	gridWidth := func(columns uint, size int) int {
+		return int(columns)*cellWidth(size) + int(columns-1)*int(spacing)
+	}
+
+	smallest := maxIconSize / 2
+	if smallest < minIconSize {
+		smallest = minIconSize
+	}
+	if smallest > maxIconSize {
+		smallest = maxIconSize
+	}
+
+	columns, size := maxColumns, maxIconSize
+	// below the minimal cell width smaller icons don't save any room
+	for size > smallest && size+cellPadding > scaleToText(minCellWidth) && gridWidth(columns, size) > width {
+		size -= 8
+	}
+	if size < smallest {
+		size = smallest
+	}
+	for columns > 1 && gridWidth(columns, size) > width {
+		columns--
+	}
+	return columns, size
+}
+
+// Height of a grid row besides the icon and the label: button padding
+const rowPadding = 38
+
+// Height of the window taken by the search entry and the status line besides
+// their text
+const pageChrome = 124
+
+// Returns the height of a grid row with icons of the size
+func rowHeight(size int) int {
+	return size + rowPadding + lineHeight
+}
+
+// Returns the height of the window taken by the search entry and the status
+// line
+func chromeHeight() int {
+	return pageChrome + 2*lineHeight
+}
+
+// Adaptive icon sizes, if enabled in the config file:
+//
+//	[grid]
+//	adaptive = true # icons as big as fit all entries on one page
+//	min-icon-size = 32
+//	max-icon-size = 128
+//
+// Zooming changes the text only then.
+func adaptiveIconSizes() (min, max int, ok bool) {
+	if !cfg.Bool("grid", "adaptive", false) {
+		return 0, 0, false
+	}
+	min = cfg.Int("grid", "min-icon-size", minIconSize)
+	max = cfg.Int("grid", "max-icon-size", 128)
+	if min < 16 {
+		min = 16
+	}
+	if max < min {
+		max = min
+	}
+	return min, max, true
+}
+
+// Returns the number of columns and the biggest icon size between minSize and
+// maxSize at which count entries, and extraRows more rows, fit into width and
+// height without scrolling. When none does, the icons are minSize.
+func fitPage(width, height, count, extraRows int, maxColumns uint, minSize, maxSize int, spacing uint) (uint, int) {
+	for size := maxSize; size > minSize; size -= 8 {
+		c, s := fitGrid(width, maxColumns, size, spacing)
+		rows := (count+int(c)-1)/int(c) + extraRows
+		if rows*rowHeight(s)+(rows-1)*int(spacing) <= height {
+			return c, s
+		}
+	}
+	return fitGrid(width, maxColumns, minSize, spacing)
+}
+
+// Window size last laid out for
+var windowWidth, windowHeight int
+
+// Fits the grid to the new window size, after a resolution or scale change or
+// a move to another output
+func relayout(width, height int) {
+	windowWidth, windowHeight = width, height
+	textChanged := measureText()
+	fitPreview(width)
+	width -= previewSpace(width)
+	c, size := fitGrid(width, settings.Columns, zoomedIconSize(), settings.Spacing)
+	if min, max, ok := adaptiveIconSizes(); ok && height < math.MaxInt32 {
+		snapshot := currentEntries()
+		extraRows := 0
+		if suggestedCount > 0 {
+			extraRows = 1
+		}
+		if recentCount > 0 {
+			extraRows++
+		}
+		if pinnedCount > 0 && settings.Columns > 0 {
+			// pinned rows hold at most the columns wanted
+			extraRows += (pinnedCount + int(settings.Columns) - 1) / int(settings.Columns)
+		}
+		c, size = fitPage(width, height-chromeHeight(), len(snapshot.Entries())-snapshot.Hidden(), extraRows,
+			settings.Columns, min, max, settings.Spacing)
+	}
+	if c == columns && size == iconSize && !textChanged {
+		return
+	}
+	log.Printf("Window %dx%d: %d columns, icon size %d\n", width, height, c, size)
+
+	if size != iconSize {
+		clearIconCache()
+	}
+	columns, iconSize = c, size
+	for _, flowBox := range []*gtk.FlowBox{pinnedFlowBox, suggestedFlowBox, recentFlowBox} {
+		if flowBox != nil {
+			flowBox.SetMinChildrenPerLine(columns)
+			flowBox.SetMaxChildrenPerLine(columns)
+		}
+	}
+	if appFlowBox != nil {
+		appFlowBox.SetMinChildrenPerLine(columns)
+		appFlowBox.SetMaxChildrenPerLine(columns)
+		// the grid is filled anew, the focus stays where it was
+		focused := focusedButton()
+		searchFocused := searchEntry.IsFocus()
+		setUpAppsFlowBox(phrase)
+		switch {
+		case focused >= 0 && focused < len(gridButtons):
+			gridButtons[focused].GrabFocus()
+		case searchFocused:
+			searchEntry.GrabFocusWithoutSelecting()
+		default:
+			focusOnShow()
+		}
+	}
+}
+
+// Applies the appearance flags given to another invocation
+func applySettings(args url.Values) {
+	if c, err := strconv.ParseUint(args.Get("c"), 10, 0); err == nil && c > 0 {
+		settings.Columns = uint(c)
+	}
+	if i, err := strconv.Atoi(args.Get("i")); err == nil && i > 0 {
+		settings.IconSize = i
+	}
+	if s, err := strconv.ParseUint(args.Get("s"), 10, 0); err == nil {
+		settings.Spacing = uint(s)
+	}
+	applyGridSettings()
+}
+
+// Lays the grid out for the configured columns, icon size and spacing
+func applyGridSettings() {
+	log.Printf("Settings changed: %d columns, icon size %d, spacing %d\n", settings.Columns, settings.IconSize, settings.Spacing)
+
+	for _, flowBox := range []*gtk.FlowBox{pinnedFlowBox, suggestedFlowBox, recentFlowBox, appFlowBox} {
+		if flowBox != nil {
+			flowBox.SetColumnSpacing(settings.Spacing)
+		}
+	}
+	for _, flowBox := range []*gtk.FlowBox{pinnedFlowBox, appFlowBox} {
+		if flowBox != nil {
+			flowBox.SetRowSpacing(settings.Spacing)
+		}
+	}
+
+	forceRelayout()
+}
+
+// Lays the grid out again after the configured sizes changed
+func forceRelayout() {
+	width, height := win.GetAllocatedWidth(), win.GetAllocatedHeight()
+	if !win.GetVisible() {
+		// laid out again for the actual size once shown
+		width, height = math.MaxInt32, math.MaxInt32
+	}
+	columns, iconSize = 0, 0
+	relayout(width, height)
+}
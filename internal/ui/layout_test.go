@@ -0,0 +1,57 @@
+package ui
+
+import "testing"
+
+func TestFitGrid(t *testing.T) {
+	for _, tt := range []struct {
+		width       int
+		wantColumns uint
+		wantSize    int
+	}{
+		{1920, 6, 64},
+		{724, 6, 64}, // 6*104 + 5*20
+		{700, 6, 56}, // icons shrink first
+		{600, 5, 56}, // then columns are dropped
+		{50, 1, 56},
+	} {
+		columns, size := fitGrid(tt.width, 6, 64, 20)
+		if columns != tt.wantColumns || size != tt.wantSize {
+			t.Errorf("width %d: got %d columns of %d, expected %d of %d", tt.width, columns, size, tt.wantColumns, tt.wantSize)
+		}
+	}
+}
+
+func TestFitPage(t *testing.T) {
+	for _, tt := range []struct {
+		height, count, extraRows int
+		wantColumns              uint
+		wantSize                 int
+	}{
+		{900, 6, 0, 6, 128},  // one row, as big as allowed
+		{900, 30, 0, 6, 104}, // 5 rows of 160 and 4 spacings
+		{900, 42, 0, 6, 48},  // 7 rows
+		{900, 600, 0, 6, 32}, // too many, the smallest
+		{500, 12, 1, 6, 96},  // 2 rows under the suggested one
+	} {
+		columns, size := fitPage(1920, tt.height, tt.count, tt.extraRows, 6, 32, 128, 20)
+		if columns != tt.wantColumns || size != tt.wantSize {
+			t.Errorf("%d entries in %d: got %d columns of %d, expected %d of %d", tt.count, tt.height, columns, size, tt.wantColumns, tt.wantSize)
+		}
+	}
+}
+
+func TestLayoutWithBigText(t *testing.T) {
+	defer func(height int) { lineHeight = height }(lineHeight)
+	lineHeight = 2 * defaultLineHeight
+
+	if got := cellWidth(64); got != 200 {
+		t.Errorf("expected cells wide enough for twice as big names, got %d", got)
+	}
+	if got := rowHeight(64); got != 64+rowPadding+36 {
+		t.Errorf("expected rows tall enough for twice as big names, got %d", got)
+	}
+	// 4*200 + 3*20 fit, 5 columns don't
+	if columns, size := fitGrid(900, 6, 64, 20); columns != 4 || size != 64 {
+		t.Errorf("got %d columns of %d, expected 4 of 64", columns, size)
+	}
+}
@@ -0,0 +1,28 @@
+package ui
+
+import (
+	"sync/atomic"
+
+	"github.com/ftphikari/wlaunchpad/internal/entries"
+	"github.com/ftphikari/wlaunchpad/internal/ipc"
+)
+
+// The current *entries.Snapshot, replaced whole when a rescan completes
+var model atomic.Value
+
+// The ipc.Warnings of the last scan, answered from the socket's goroutines
+var scanWarnings atomic.Value
+
+func currentEntries() *entries.Snapshot {
+	if snapshot, ok := model.Load().(*entries.Snapshot); ok {
+		return snapshot
+	}
+	return entries.NewSnapshot(nil)
+}
+
+func currentWarnings() ipc.Warnings {
+	if warnings, ok := scanWarnings.Load().(ipc.Warnings); ok {
+		return warnings
+	}
+	return ipc.NewWarnings(nil, nil)
+}
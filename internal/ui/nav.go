@@ -0,0 +1,211 @@
+package ui
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/gotk3/gotk3/gdk"
+
+	"github.com/ftphikari/wlaunchpad/internal/entries"
+	"github.com/ftphikari/wlaunchpad/internal/gamepad"
+)
+
+// Returns the index of the focused grid button, -1 if none has the focus
+func focusedButton() int {
+	for i, ab := range gridButtons {
+		if ab.HasFocus() {
+			return i
+		}
+	}
+	return -1
+}
+
+// Moves the focus by dx columns and dy rows, wrapping around at the ends of
+// rows and columns
+func moveFocus(dx, dy int) {
+	if len(gridButtons) == 0 {
+		return
+	}
+	i := focusedButton()
+	if i == -1 {
+		gridButtons[0].GrabFocus()
+		return
+	}
+	sections := []int{pinnedCount, suggestedCount, recentCount, len(gridButtons) - pinnedCount - suggestedCount - recentCount}
+	if j := gridMove(i, dx, dy, sections, int(columns)); j != i {
+		gridButtons[j].GrabFocus()
+	}
+}
+
+// Returns the index reached from button i by moving dx columns and dy rows in
+// a grid of sections stacked on top of each other, like the pinned row, the
+// suggested and recently used rows and the rest, with the number of buttons of
+// each. Every section is laid out in rows of rowLength, its last row may be
+// shorter. Left and right go through the buttons in reading order, up and down
+// stay in the column, ending on the last button of shorter rows.
+func gridMove(i, dx, dy int, sections []int, rowLength int) int {
+	count := 0
+	for _, n := range sections {
+		count += n
+	}
+	if count == 0 || rowLength < 1 {
+		return i
+	}
+	if dx != 0 {
+		i = ((i+dx)%count + count) % count
+	}
+	if dy == 0 {
+		return i
+	}
+
+	// start index of every row
+	var rows []int
+	start := 0
+	for _, n := range sections {
+		for j := 0; j < n; j += rowLength {
+			rows = append(rows, start+j)
+		}
+		start += n
+	}
+	row := len(rows) - 1
+	for rows[row] > i {
+		row--
+	}
+	column := i - rows[row]
+
+	row = ((row+dy)%len(rows) + len(rows)) % len(rows)
+	end := count
+	if row+1 < len(rows) {
+		end = rows[row+1]
+	}
+	if rows[row]+column >= end {
+		return end - 1
+	}
+	return rows[row] + column
+}
+
+// Returns the columns and rows an arrow key moves by
+func arrowDirection(key uint) (dx, dy int) {
+	switch key {
+	case gdk.KEY_Up:
+		return 0, -1
+	case gdk.KEY_Down:
+		return 0, 1
+	case gdk.KEY_Left:
+		return -1, 0
+	case gdk.KEY_Right:
+		return 1, 0
+	}
+	return 0, 0
+}
+
+func handleGamepad(ev gamepad.Event) {
+	if !win.GetVisible() {
+		return
+	}
+
+	switch ev {
+	case gamepad.Up:
+		moveFocus(0, -1)
+	case gamepad.Down:
+		moveFocus(0, 1)
+	case gamepad.Left:
+		moveFocus(-1, 0)
+	case gamepad.Right:
+		moveFocus(1, 0)
+	case gamepad.Activate:
+		if i := focusedButton(); i != -1 {
+			gridButtons[i].run()
+		}
+	case gamepad.Back:
+		if s, _ := searchEntry.GetText(); s != "" {
+			searchEntry.SetText("")
+		} else {
+			closeWindow()
+		}
+	}
+}
+
+// Focuses the first entry or the search entry when the window is shown, as
+// set in the config file:
+//
+//	[browse]
+//	focus = "search" # "grid" by default
+func focusOnShow() {
+	if cfg.Str("browse", "focus", "grid") == "search" {
+		searchEntry.GrabFocusWithoutSelecting()
+	} else {
+		focusFirstItem()
+	}
+}
+
+// In browse mode typing a letter while a grid button has the focus jumps to
+// the next entry starting with it, instead of starting a search, and a slash
+// goes to the search:
+//
+//	[browse]
+//	jump-by-letter = true
+func jumpToLetter(r rune) bool {
+	if !jumpByLetter() || !unicode.IsPrint(r) || unicode.IsSpace(r) {
+		return false
+	}
+	if r == '/' {
+		searchEntry.GrabFocusWithoutSelecting()
+		return true
+	}
+	current := focusedButton()
+	if current == -1 {
+		return false
+	}
+
+	names := make([]string, len(gridButtons))
+	for i, ab := range gridButtons {
+		names[i] = ab.entry.NameLoc
+	}
+	if next := nextWithPrefix(names, current, r); next != -1 {
+		gridButtons[next].GrabFocus()
+	}
+	return true
+}
+
+func jumpByLetter() bool {
+	return cfg.Bool("browse", "jump-by-letter", false)
+}
+
+// Sections by first letter of the full grid, sorted by name, nil otherwise.
+// Their starts count from the button at sectionsStart.
+var (
+	letterSections []entries.Section
+	sectionsStart  int
+)
+
+// Ctrl+<letter> focuses the first entry filed under the letter, or the next
+// one with entries, in the full grid. Reports whether the key was taken.
+func jumpToSection(r rune) bool {
+	if letterSections == nil || !unicode.IsLetter(r) {
+		return false
+	}
+	if searchEntry.IsFocus() {
+		// the rest are for a phrase, see handleEditingKey
+		if s, _ := searchEntry.GetText(); s != "" {
+			return false
+		}
+	}
+	if section, ok := entries.SectionFor(letterSections, r); ok {
+		gridButtons[sectionsStart+section.Start].GrabFocus()
+	}
+	return true
+}
+
+// Returns the index of the first name after current starting with r, wrapping
+// around, -1 if there's none
+func nextWithPrefix(names []string, current int, r rune) int {
+	prefix := strings.ToLower(string(r))
+	for n := 1; n <= len(names); n++ {
+		i := (current + n) % len(names)
+		if strings.HasPrefix(strings.ToLower(names[i]), prefix) {
+			return i
+		}
+	}
+	return -1
+}
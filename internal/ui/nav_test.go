@@ -0,0 +1,80 @@
+package ui
+
+import "testing"
+
+func TestNextWithPrefix(t *testing.T) {
+	names := []string{"Firefox", "foot", "GIMP", "Files"}
+	for _, tt := range []struct {
+		current int
+		r       rune
+		want    int
+	}{
+		{0, 'f', 1},
+		{1, 'F', 3},
+		{3, 'f', 0}, // wraps around
+		{0, 'g', 2},
+		{2, 'g', 2}, // the only one
+		{0, 'z', -1},
+	} {
+		if got := nextWithPrefix(names, tt.current, tt.r); got != tt.want {
+			t.Errorf("nextWithPrefix(%d, %q) = %d, expected %d", tt.current, tt.r, got, tt.want)
+		}
+	}
+}
+
+func TestGridMove(t *testing.T) {
+	// 3 suggested, then rows of 4, the last one holding 2:
+	//
+	//	0 1 2
+	//	3 4 5 6
+	//	7 8 9 10
+	//	11 12
+	for _, tt := range []struct {
+		from, dx, dy, want int
+	}{
+		{4, 1, 0, 5},
+		{6, 1, 0, 7},   // on to the next row
+		{12, 1, 0, 0},  // wraps around to the first
+		{0, -1, 0, 12}, // and back
+		{5, 0, 1, 9},
+		{9, 0, 1, 12}, // the last row is shorter
+		{12, 0, 1, 1}, // wraps around to the top
+		{1, 0, -1, 12},
+		{6, 0, -1, 2}, // the suggested row is shorter
+		{2, 0, 1, 5},
+		{11, 0, 1, 0},
+	} {
+		if got := gridMove(tt.from, tt.dx, tt.dy, []int{3, 10}, 4); got != tt.want {
+			t.Errorf("gridMove(%d, %d, %d) = %d, expected %d", tt.from, tt.dx, tt.dy, got, tt.want)
+		}
+	}
+
+	// no suggested row
+	if got := gridMove(1, 0, 1, []int{0, 6}, 4); got != 5 {
+		t.Errorf("gridMove without suggestions = %d, expected 5", got)
+	}
+	if got := gridMove(3, 0, 1, []int{0, 6}, 4); got != 5 {
+		t.Errorf("gridMove into the last row = %d, expected 5", got)
+	}
+
+	// 5 pinned wrapping onto a second row, 2 suggested, then 4:
+	//
+	//	0 1 2 3
+	//	4
+	//	5 6
+	//	7 8 9 10
+	for _, tt := range []struct {
+		from, dy, want int
+	}{
+		{1, 1, 4}, // the pinned row wraps
+		{4, 1, 5},
+		{6, 1, 8},
+		{8, -1, 6},
+		{6, -1, 4},
+		{9, 1, 2}, // wraps around to the top
+	} {
+		if got := gridMove(tt.from, 0, tt.dy, []int{5, 2, 4}, 4); got != tt.want {
+			t.Errorf("gridMove(%d, 0, %d) with pins = %d, expected %d", tt.from, tt.dy, got, tt.want)
+		}
+	}
+}
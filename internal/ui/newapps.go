@@ -0,0 +1,96 @@
+package ui
+
+import (
+	"fmt"
+	"log"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/gotk3/gotk3/glib"
+
+	"github.com/ftphikari/wlaunchpad/internal/config"
+	"github.com/ftphikari/wlaunchpad/internal/entries"
+)
+
+// How long a newly installed app keeps its "NEW" badge
+const newBadgeAge = 24 * time.Hour
+
+// How often the daemon checks application directories for new apps
+const newAppsInterval = 30 * time.Second
+
+var (
+	firstSeen entries.FirstSeen
+	lastScan  time.Time
+)
+
+// New apps are announced if enabled in the config file:
+//
+//	[new-apps]
+//	notify = true # desktop notification when an app gets installed
+//	badge = true  # "NEW" badge for a day
+//	hint = "press Super to launch"
+func newAppsEnabled() bool {
+	return cfg.Bool("new-apps", "notify", false) || newBadgesEnabled()
+}
+
+func newBadgesEnabled() bool {
+	return cfg.Bool("new-apps", "badge", false)
+}
+
+func firstSeenPath() string {
+	return filepath.Join(config.StateDir(), "first-seen")
+}
+
+// Records the scanned entries, announcing the ones not seen before
+func checkNewApps(scanned []entries.DesktopEntry) {
+	lastScan = time.Now()
+	if !newAppsEnabled() {
+		return
+	}
+	if firstSeen == nil {
+		var err error
+		firstSeen, err = entries.LoadFirstSeen(firstSeenPath())
+		if err != nil {
+			log.Printf("Couldn't read %s: %s", firstSeenPath(), err)
+		}
+	}
+
+	added := firstSeen.Update(scanned, lastScan)
+	if len(added) == 0 {
+		return
+	}
+	if err := firstSeen.Save(firstSeenPath()); err != nil {
+		log.Printf("Couldn't save %s: %s", firstSeenPath(), err)
+	}
+	if !cfg.Bool("new-apps", "notify", false) {
+		return
+	}
+	for _, entry := range added {
+		log.Printf("New app installed: %s\n", entry.DesktopID)
+		body := entry.NameLoc
+		if hint := cfg.Str("new-apps", "hint", ""); hint != "" {
+			body = fmt.Sprintf("%s — %s", body, hint)
+		}
+		go exec.Command("notify-send", "-a", "wlaunchpad", "-i", entry.Icon, "New app installed", body).Run()
+	}
+}
+
+func isNewApp(id string) bool {
+	if !newBadgesEnabled() || firstSeen == nil {
+		return false
+	}
+	seen, ok := firstSeen[id]
+	return ok && time.Since(seen) < newBadgeAge
+}
+
+// The daemon doesn't scan until shown, so new apps are looked for in the
+// background too
+func watchNewApps() {
+	glib.TimeoutAdd(uint(newAppsInterval/time.Millisecond), func() bool {
+		if !win.GetVisible() && entries.LastModified().After(lastScan) {
+			parseDesktopFiles()
+		}
+		return true
+	})
+}
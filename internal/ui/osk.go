@@ -0,0 +1,38 @@
+package ui
+
+import (
+	"fmt"
+	"log"
+	"os/exec"
+
+	"github.com/gotk3/gotk3/gtk"
+
+	"github.com/ftphikari/wlaunchpad/internal/launch"
+)
+
+// Shows squeekboard (over D-Bus) or wvkbd (which shows on SIGUSR2). Can be
+// replaced with the command key in the [osk] section of the config file.
+const defaultOSKCommand = `busctl call --user sm.puri.OSK0 /sm/puri/OSK0 sm.puri.OSK0 SetVisible b true || pkill -USR2 wvkbd`
+
+// Returns a button summoning the on-screen keyboard, for touch-only devices
+// where nothing else would bring it up
+func oskButton() *gtk.Button {
+	button, _ := gtk.ButtonNewFromIconName("input-keyboard-symbolic", gtk.ICON_SIZE_BUTTON)
+	button.SetTooltipText("Show on-screen keyboard")
+	button.SetCanFocus(false)
+	button.Connect("clicked", func() {
+		searchEntry.GrabFocusWithoutSelecting()
+		command := cfg.Str("osk", "command", defaultOSKCommand)
+		cmd := exec.Command("sh", "-c", command)
+		if settings.DryRun {
+			fmt.Print(launch.Describe(cmd))
+			return
+		}
+		if err := cmd.Start(); err != nil {
+			log.Printf("Couldn't show the on-screen keyboard: %s", err)
+			return
+		}
+		launch.Reap(cmd)
+	})
+	return button
+}
@@ -0,0 +1,193 @@
+package ui
+
+import (
+	"context"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gotk3/gotk3/gdk"
+	"github.com/gotk3/gotk3/glib"
+
+	"github.com/ftphikari/wlaunchpad/internal/compositor"
+)
+
+// Whether GDK talks Wayland, see detectBackend
+var waylandBackend bool
+
+func wayland() bool {
+	return waylandBackend
+}
+
+// Picks the GDK backend before GTK starts: the one given with -backend, else
+// Wayland if a Wayland socket accepts connections, whatever the environment
+// says. Returns a function putting GDK_BACKEND back once GTK has started, so
+// launched apps don't inherit our choice.
+func detectBackend() (restore func()) {
+	backend := settings.Backend
+	switch backend {
+	case "wayland", "x11":
+		log.Printf("Backend: %s, as given with -backend", backend)
+	default:
+		backend = "x11"
+		socket, err := compositor.WaylandSocket()
+		if err == nil {
+			backend = "wayland"
+			log.Printf("Backend: wayland, %s accepts connections", socket)
+			runtimeDir := os.Getenv("XDG_RUNTIME_DIR")
+			if display := os.Getenv("WAYLAND_DISPLAY"); socket != display && socket != filepath.Join(runtimeDir, display) {
+				// apps we start need it as well
+				if filepath.Dir(socket) == runtimeDir {
+					socket = filepath.Base(socket)
+				}
+				log.Printf("Setting WAYLAND_DISPLAY=%s, was %q", socket, display)
+				os.Setenv("WAYLAND_DISPLAY", socket)
+			}
+		} else {
+			log.Printf("Backend: x11, %s (WAYLAND_DISPLAY=%q, XDG_SESSION_TYPE=%q)", err,
+				os.Getenv("WAYLAND_DISPLAY"), os.Getenv("XDG_SESSION_TYPE"))
+		}
+	}
+	waylandBackend = backend == "wayland"
+
+	previous, set := os.LookupEnv("GDK_BACKEND")
+	os.Setenv("GDK_BACKEND", backend)
+	return func() {
+		if set {
+			os.Setenv("GDK_BACKEND", previous)
+		} else {
+			os.Unsetenv("GDK_BACKEND")
+		}
+	}
+}
+
+// Attempts at a compositor IPC request, waiting twice as long after each
+// failure
+const (
+	ipcAttempts = 3
+	ipcBackoff  = 100 * time.Millisecond
+)
+
+// Returns the time a compositor IPC request may take, from the config file:
+//
+//	[compositor]
+//	timeout = 500 # milliseconds
+func ipcTimeout() time.Duration {
+	// [sway] is what older config files have
+	return time.Duration(cfg.Int("compositor", "timeout", cfg.Int("sway", "timeout", 500))) * time.Millisecond
+}
+
+// Calls f until it succeeds, backing off between attempts. Busy systems
+// answer late rather than never.
+func withRetries(f func(ctx context.Context) error) error {
+	var err error
+	backoff := ipcBackoff
+	for attempt := 1; attempt <= ipcAttempts; attempt++ {
+		err = func() error {
+			ctx, cancel := context.WithTimeout(context.Background(), ipcTimeout())
+			defer cancel()
+			return f(ctx)
+		}()
+		if err == nil {
+			return nil
+		}
+		log.Printf("Compositor IPC attempt %d failed: %s", attempt, err)
+		if attempt < ipcAttempts {
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+	}
+	return err
+}
+
+// Outputs as last fetched, read and written on the main thread
+var knownOutputs []compositor.Output
+
+// Puts the window on the output given with -o. The outputs are fetched from
+// the compositor without blocking the main loop, and again whenever a monitor
+// gets plugged in or out.
+func placeOnTargetOutput() {
+	if settings.TargetOutput == "" {
+		return
+	}
+	refreshOutputs()
+
+	display, err := gdk.DisplayGetDefault()
+	if err != nil {
+		log.Print(err)
+		return
+	}
+	display.Connect("monitor-added", refreshOutputs)
+	display.Connect("monitor-removed", refreshOutputs)
+}
+
+func refreshOutputs() {
+	go func() {
+		backend := compositor.Detect()
+		var outputs []compositor.Output
+		err := withRetries(func(ctx context.Context) error {
+			var err error
+			outputs, err = backend.Outputs(ctx)
+			return err
+		})
+		glib.IdleAdd(func() bool {
+			if err == nil {
+				knownOutputs = outputs
+			} else if knownOutputs != nil {
+				log.Printf("Couldn't get outputs, using the ones last seen: %s", err)
+			} else {
+				log.Printf("Couldn't get outputs from %s, staying on the current one: %s", backend.Name(), err)
+				return false
+			}
+			setTargetMonitor()
+			return false
+		})
+	}()
+}
+
+func setTargetMonitor() {
+	if hibernating {
+		// placed once woken up
+		return
+	}
+	output2mon, err := mapOutputs(knownOutputs)
+	if err != nil {
+		log.Print(err)
+		return
+	}
+	monitor, ok := output2mon[settings.TargetOutput]
+	if !ok {
+		log.Printf("Output %s not found", settings.TargetOutput)
+		return
+	}
+	setMonitor(monitor)
+	if win.GetVisible() {
+		// a mapped layer surface moves to another output only when mapped again
+		win.Hide()
+		win.ShowAll()
+	}
+}
+
+// Returns map output name -> gdk.Monitor
+func mapOutputs(outputs []compositor.Output) (map[string]*gdk.Monitor, error) {
+	result := make(map[string]*gdk.Monitor)
+
+	display, err := gdk.DisplayGetDefault()
+	if err != nil {
+		return nil, err
+	}
+
+	num := display.GetNMonitors()
+	for i := 0; i < num; i++ {
+		monitor, _ := display.GetMonitor(i)
+		geometry := monitor.GetGeometry()
+		// assign output to monitor on the basis of the same x, y coordinates
+		for _, output := range outputs {
+			if output.X == geometry.GetX() && output.Y == geometry.GetY() {
+				result[output.Name] = monitor
+			}
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,104 @@
+package ui
+
+import (
+	"log"
+	"path/filepath"
+
+	"github.com/gotk3/gotk3/gtk"
+
+	"github.com/ftphikari/wlaunchpad/internal/config"
+	"github.com/ftphikari/wlaunchpad/internal/entries"
+)
+
+var (
+	pins          entries.Pins
+	pinnedRow     *gtk.Box
+	pinnedFlowBox *gtk.FlowBox
+	// buttons at the start of gridButtons which are in pinnedFlowBox
+	pinnedCount int
+)
+
+func pinsPath() string {
+	return filepath.Join(config.DataDir(), "pinned")
+}
+
+func loadPins() {
+	var err error
+	pins, err = entries.LoadPins(pinsPath())
+	if err != nil {
+		log.Printf("Couldn't read %s: %s", pinsPath(), err)
+	}
+}
+
+func isPinned(entry entries.DesktopEntry) bool {
+	return pins.Contains(entry.StateID())
+}
+
+// Pins or unpins the entry and refreshes the grid
+func setPinned(entry entries.DesktopEntry, pinned bool) {
+	if pinned {
+		pins = pins.Add(entry.StateID())
+	} else {
+		pins = pins.Remove(entry.StateID())
+	}
+	if err := pins.Save(pinsPath()); err != nil {
+		log.Printf("Couldn't save %s: %s", pinsPath(), err)
+	}
+	setUpAppsFlowBox(phrase)
+	focusFirstItem()
+}
+
+// Creates the row of pinned entries, shown above the suggested row
+func newPinnedRow() *gtk.Box {
+	pinnedRow, _ = gtk.BoxNew(gtk.ORIENTATION_VERTICAL, 0)
+	pinnedRow.SetNoShowAll(true)
+
+	heading, _ := gtk.LabelNew("Pinned")
+	heading.SetHAlign(gtk.ALIGN_START)
+	ctx, _ := heading.GetStyleContext()
+	ctx.AddClass("dim-label")
+	heading.Show()
+	pinnedRow.PackStart(heading, false, false, 0)
+
+	pinnedFlowBox, _ = gtk.FlowBoxNew()
+	pinnedFlowBox.SetColumnSpacing(settings.Spacing)
+	pinnedFlowBox.SetRowSpacing(settings.Spacing)
+	pinnedFlowBox.SetHomogeneous(true)
+	pinnedFlowBox.SetSelectionMode(gtk.SELECTION_NONE)
+	pinnedFlowBox.SetMinChildrenPerLine(columns)
+	pinnedFlowBox.SetMaxChildrenPerLine(columns)
+	pinnedFlowBox.SetHAlign(gtk.ALIGN_CENTER)
+	pinnedFlowBox.Show()
+	pinnedRow.PackStart(pinnedFlowBox, false, false, 0)
+	return pinnedRow
+}
+
+// Fills the pinned row with the pinned entries shown would keep, the first
+// buttons added to the grid. Searching narrows it down like the grid.
+func setUpPinned(snapshot *entries.Snapshot, shown func(entries.DesktopEntry) bool) {
+	pinnedCount = 0
+	if pinnedRow == nil {
+		return
+	}
+
+	var pinned []entries.DesktopEntry
+	for _, entry := range pins.Entries(snapshot) {
+		if shown(entry) {
+			pinned = append(pinned, entry)
+		}
+	}
+	if len(pinned) == 0 {
+		pinnedRow.Hide()
+		return
+	}
+	pinnedRow.Show()
+
+	for _, entry := range pinned {
+		pinnedFlowBox.Add(getAppButton(entry))
+	}
+	pinnedCount = len(pinned)
+	pinnedFlowBox.GetChildren().Foreach(func(item interface{}) {
+		item.(*gtk.Widget).SetCanFocus(false)
+	})
+	pinnedFlowBox.ShowAll()
+}
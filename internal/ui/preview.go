@@ -0,0 +1,140 @@
+package ui
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"github.com/gotk3/gotk3/gtk"
+
+	"github.com/ftphikari/wlaunchpad/internal/entries"
+)
+
+// Preview of the focused entry at the right of the grid, if enabled in the
+// config file. It shows only on windows at least min-width wide.
+//
+//	[preview]
+//	enabled = true
+//	min-width = 2400
+//	icon-size = 192
+var (
+	previewPane    *gtk.Box
+	previewImage   *gtk.Image
+	previewName    *gtk.Label
+	previewDetails *gtk.Label
+)
+
+// Width of the preview pane, margins included
+const previewWidth = 400
+
+func previewEnabled() bool {
+	return cfg.Bool("preview", "enabled", false)
+}
+
+// Returns the width the preview takes out of a window of the width, 0 when it
+// doesn't show
+func previewSpace(width int) int {
+	if !previewEnabled() || width < cfg.Int("preview", "min-width", 2400) {
+		return 0
+	}
+	return previewWidth
+}
+
+func newPreviewPane() *gtk.Box {
+	previewPane, _ = gtk.BoxNew(gtk.ORIENTATION_VERTICAL, 10)
+	previewPane.SetSizeRequest(previewWidth-20, -1)
+	previewPane.SetMarginEnd(20)
+	previewPane.SetNoShowAll(true)
+	ctx, _ := previewPane.GetStyleContext()
+	ctx.AddClass("preview")
+
+	previewImage, _ = gtk.ImageNew()
+	previewPane.PackStart(previewImage, false, false, 0)
+	previewName, _ = gtk.LabelNew("")
+	previewName.SetLineWrap(true)
+	previewPane.PackStart(previewName, false, false, 0)
+	previewDetails, _ = gtk.LabelNew("")
+	previewDetails.SetLineWrap(true)
+	previewDetails.SetMaxWidthChars(40)
+	previewDetails.SetXAlign(0)
+	previewPane.PackStart(previewDetails, false, false, 0)
+	for _, w := range []gtk.IWidget{previewImage, previewName, previewDetails} {
+		w.ToWidget().Show()
+	}
+	return previewPane
+}
+
+// Shows or hides the preview for the window width
+func fitPreview(width int) {
+	if previewPane == nil {
+		return
+	}
+	previewPane.SetVisible(previewSpace(width) > 0)
+}
+
+// Shows the entry in the preview, if it's there
+func showPreview(entry entries.DesktopEntry) {
+	if previewPane == nil || !previewPane.GetVisible() {
+		return
+	}
+	if pixbuf, err := createPixbuf(entryIcon(entry), cfg.Int("preview", "icon-size", 192)); err == nil {
+		previewImage.SetFromPixbuf(pixbuf)
+	} else {
+		previewImage.Clear()
+	}
+	previewName.SetMarkup(fmt.Sprintf("<big><b>%s</b></big>", html.EscapeString(entry.NameLoc)))
+	previewDetails.SetMarkup(previewText(entry, history[entry.StateID()], time.Now()))
+}
+
+// Returns the details of an entry shown under its name, as markup
+func previewText(entry entries.DesktopEntry, launches entries.Launches, now time.Time) string {
+	var lines []string
+	if entry.GenericNameLoc != "" {
+		lines = append(lines, html.EscapeString(entry.GenericNameLoc))
+	}
+	if entry.CommentLoc != "" {
+		lines = append(lines, html.EscapeString(entry.CommentLoc))
+	}
+	lines = append(lines, "")
+	if categories := strings.Trim(entry.Category, ";"); categories != "" {
+		lines = append(lines, "<b>Categories</b> "+html.EscapeString(strings.ReplaceAll(categories, ";", ", ")))
+	}
+	source := "built in"
+	if entry.Path != "" {
+		source = entry.Path
+	}
+	lines = append(lines, "<b>Source</b> "+html.EscapeString(source))
+	switch launches.Count {
+	case 0:
+		lines = append(lines, "<b>Launched</b> never")
+	case 1:
+		lines = append(lines, "<b>Launched</b> once, "+timeAgo(launches.Last, now))
+	default:
+		lines = append(lines, fmt.Sprintf("<b>Launched</b> %d times, last %s", launches.Count, timeAgo(launches.Last, now)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Returns how long ago t was, roughly
+func timeAgo(t, now time.Time) string {
+	d := now.Sub(t)
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return plural(int(d/time.Minute), "minute") + " ago"
+	case d < 24*time.Hour:
+		return plural(int(d/time.Hour), "hour") + " ago"
+	case d < 30*24*time.Hour:
+		return plural(int(d/(24*time.Hour)), "day") + " ago"
+	}
+	return "on " + t.Format("2 Jan 2006")
+}
+
+func plural(n int, unit string) string {
+	if n == 1 {
+		return "1 " + unit
+	}
+	return fmt.Sprintf("%d %ss", n, unit)
+}
@@ -0,0 +1,51 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ftphikari/wlaunchpad/internal/entries"
+)
+
+func TestTimeAgo(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	for _, tt := range []struct {
+		ago  time.Duration
+		want string
+	}{
+		{10 * time.Second, "just now"},
+		{time.Minute, "1 minute ago"},
+		{3 * time.Hour, "3 hours ago"},
+		{50 * time.Hour, "2 days ago"},
+		{60 * 24 * time.Hour, "on 10 Jan 2024"},
+	} {
+		if got := timeAgo(now.Add(-tt.ago), now); got != tt.want {
+			t.Errorf("%s ago: got %q, expected %q", tt.ago, got, tt.want)
+		}
+	}
+}
+
+func TestPreviewText(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	entry := entries.DesktopEntry{
+		CommentLoc: "Browse <the> web",
+		Category:   "Network;WebBrowser;",
+		Path:       "/usr/share/applications/firefox.desktop",
+	}
+	text := previewText(entry, entries.Launches{Count: 3, Last: now.Add(-2 * time.Hour)}, now)
+	for _, want := range []string{
+		"Browse &lt;the&gt; web",
+		"<b>Categories</b> Network, WebBrowser",
+		"<b>Source</b> /usr/share/applications/firefox.desktop",
+		"<b>Launched</b> 3 times, last 2 hours ago",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in:\n%s", want, text)
+		}
+	}
+
+	if text := previewText(entries.DesktopEntry{}, entries.Launches{}, now); !strings.Contains(text, "built in") || !strings.Contains(text, "never") {
+		t.Errorf("unexpected text for a synthetic entry:\n%s", text)
+	}
+}
@@ -0,0 +1,159 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ftphikari/wlaunchpad/internal/entries"
+)
+
+// A source of grid entries. Each one can be configured in a section of the
+// config file named after it:
+//
+//	[provider.screenshot]
+//	enabled = true
+//	prefix = "ss "  # shown only when the search starts with it
+//	limit = 3       # at most as many in the grid
+//	position = -1   # lower comes first, the default is 0
+type provider struct {
+	name string
+	// Returns the entries given the desktop file ones, nil for "apps"
+	entries func(apps []entries.DesktopEntry) []entries.DesktopEntry
+	// Whether it is enabled when the config file doesn't say
+	enabled func() bool
+}
+
+var providers = []provider{
+	{"apps", nil, func() bool { return true }},
+	{"sets", sessionSetEntries, func() bool { return true }},
+	// [screenshot] and [colorpicker] enabled = true is what older config
+	// files have
+	{"screenshot", screenshotEntries, func() bool { return cfg.Bool("screenshot", "enabled", false) }},
+	{"colorpicker", colorPickerEntries, func() bool { return cfg.Bool("colorpicker", "enabled", false) }},
+}
+
+// Keys of a provider section
+var providerKeys = []string{"enabled", "limit", "position", "prefix"}
+
+func providerSection(name string) string {
+	return "provider." + name
+}
+
+func providerEnabled(p provider) bool {
+	return cfg.Bool(providerSection(p.name), "enabled", p.enabled())
+}
+
+func providerPrefix(name string) string {
+	return strings.ToLower(cfg.Str(providerSection(name), "prefix", ""))
+}
+
+func providerLimit(name string) int {
+	return cfg.Int(providerSection(name), "limit", 0)
+}
+
+func providerPosition(name string) int {
+	return cfg.Int(providerSection(name), "position", 0)
+}
+
+// Returns the entries of the enabled providers, given the desktop file ones
+func providerEntries(apps []entries.DesktopEntry) []entries.DesktopEntry {
+	var list []entries.DesktopEntry
+	for _, p := range providers {
+		if !providerEnabled(p) {
+			continue
+		}
+		found := apps
+		if p.entries != nil {
+			found = p.entries(apps)
+		}
+		for _, entry := range found {
+			entry.Provider = p.name
+			list = append(list, entry)
+		}
+	}
+	return list
+}
+
+// Returns the provider whose prefix the search phrase starts with and the rest
+// of the phrase, "" and the whole phrase if none
+func prefixedProvider(searchPhrase string) (string, string) {
+	lower := strings.ToLower(searchPhrase)
+	for _, p := range providers {
+		prefix := providerPrefix(p.name)
+		if prefix != "" && providerEnabled(p) && strings.HasPrefix(lower, prefix) {
+			return p.name, strings.TrimLeft(searchPhrase[len(prefix):], " ")
+		}
+	}
+	return "", searchPhrase
+}
+
+// Reports whether the entry belongs in the grid while the search is for the
+// provider, "" for none. Providers with a prefix only show up when it is
+// typed.
+func providerShown(entry entries.DesktopEntry, prefixed string) bool {
+	if prefixed != "" {
+		return entry.Provider == prefixed
+	}
+	return providerPrefix(entry.Provider) == ""
+}
+
+// Orders sorted entries by the position of their provider, keeping the order
+// within each, and drops those beyond the limit of their provider. Zero
+// limits mean no limit.
+func arrangeByProvider(list []entries.DesktopEntry, position, limit func(provider string) int) []entries.DesktopEntry {
+	sort.SliceStable(list, func(i, j int) bool {
+		return position(list[i].Provider) < position(list[j].Provider)
+	})
+	counts := make(map[string]int)
+	var arranged []entries.DesktopEntry
+	for _, entry := range list {
+		counts[entry.Provider]++
+		if max := limit(entry.Provider); max > 0 && counts[entry.Provider] > max {
+			continue
+		}
+		arranged = append(arranged, entry)
+	}
+	return arranged
+}
+
+// Returns what's wrong with the provider sections of the config file
+func checkProviderConfig() []error {
+	var errs []error
+	for _, name := range cfg.Subsections("provider") {
+		known := false
+		for _, p := range providers {
+			known = known || p.name == name
+		}
+		if !known {
+			var names []string
+			for _, p := range providers {
+				names = append(names, p.name)
+			}
+			errs = append(errs, fmt.Errorf("[provider.%s]: unknown provider, expected one of %s", name, strings.Join(names, ", ")))
+			continue
+		}
+		section := providerSection(name)
+		for _, key := range cfg.Keys(section) {
+			valid := false
+			for _, k := range providerKeys {
+				valid = valid || k == key
+			}
+			if !valid {
+				errs = append(errs, fmt.Errorf("[%s]: unknown key %q, expected one of %s", section, key, strings.Join(providerKeys, ", ")))
+			}
+		}
+		if cfg.Has(section, "enabled") && cfg.Bool(section, "enabled", true) != cfg.Bool(section, "enabled", false) {
+			errs = append(errs, fmt.Errorf("[%s]: enabled must be true or false", section))
+		}
+		for _, key := range []string{"limit", "position"} {
+			if cfg.Has(section, key) && cfg.Int(section, key, -1) != cfg.Int(section, key, 0) {
+				errs = append(errs, fmt.Errorf("[%s]: %s must be a number", section, key))
+			}
+		}
+		if cfg.Int(section, "limit", 0) < 0 {
+			errs = append(errs, fmt.Errorf("[%s]: limit can't be negative", section))
+		}
+	}
+	return errs
+}
@@ -0,0 +1,92 @@
+package ui
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ftphikari/wlaunchpad/internal/config"
+	"github.com/ftphikari/wlaunchpad/internal/entries"
+)
+
+func TestArrangeByProvider(t *testing.T) {
+	list := []entries.DesktopEntry{
+		{DesktopID: "a.desktop", Provider: "apps"},
+		{DesktopID: "screenshot:region", Provider: "screenshot"},
+		{DesktopID: "b.desktop", Provider: "apps"},
+		{DesktopID: "screenshot:window", Provider: "screenshot"},
+		{DesktopID: "c.desktop", Provider: "apps"},
+	}
+	position := func(provider string) int {
+		if provider == "screenshot" {
+			return -1
+		}
+		return 0
+	}
+	limit := func(provider string) int {
+		if provider == "apps" {
+			return 2
+		}
+		return 0
+	}
+	var ids []string
+	for _, entry := range arrangeByProvider(list, position, limit) {
+		ids = append(ids, entry.DesktopID)
+	}
+	want := []string{"screenshot:region", "screenshot:window", "a.desktop", "b.desktop"}
+	if !reflect.DeepEqual(ids, want) {
+		t.Errorf("got %q, want %q", ids, want)
+	}
+}
+
+func TestPrefixedProvider(t *testing.T) {
+	defer func(c config.Config) { cfg = c }(cfg)
+	cfg, _ = config.Parse(strings.NewReader(`
+[provider.screenshot]
+enabled = true
+prefix = "ss "
+`))
+
+	if provider, query := prefixedProvider("SS  region"); provider != "screenshot" || query != "region" {
+		t.Errorf("got %q, %q", provider, query)
+	}
+	if provider, query := prefixedProvider("firefox"); provider != "" || query != "firefox" {
+		t.Errorf("got %q, %q", provider, query)
+	}
+	if providerShown(entries.DesktopEntry{Provider: "screenshot"}, "") {
+		t.Error("entries of providers with a prefix should only show up once it's typed")
+	}
+	if !providerShown(entries.DesktopEntry{Provider: "screenshot"}, "screenshot") || providerShown(entries.DesktopEntry{Provider: "apps"}, "screenshot") {
+		t.Error("expected only the prefixed provider's entries")
+	}
+}
+
+func TestCheckProviderConfig(t *testing.T) {
+	defer func(c config.Config) { cfg = c }(cfg)
+	cfg, _ = config.Parse(strings.NewReader(`
+[provider.apps]
+position = 1
+
+[provider.calc]
+enabled = true
+
+[provider.sets]
+limt = 3
+limit = -1
+enabled = "yes"
+`))
+
+	var got []string
+	for _, err := range checkProviderConfig() {
+		got = append(got, err.Error())
+	}
+	want := []string{
+		"[provider.calc]: unknown provider, expected one of apps, sets, screenshot, colorpicker",
+		`[provider.sets]: unknown key "limt", expected one of enabled, limit, position, prefix`,
+		"[provider.sets]: enabled must be true or false",
+		"[provider.sets]: limit can't be negative",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
@@ -0,0 +1,81 @@
+package ui
+
+import (
+	"github.com/gotk3/gotk3/gtk"
+
+	"github.com/ftphikari/wlaunchpad/internal/entries"
+)
+
+var (
+	recentExpander *gtk.Expander
+	recentFlowBox  *gtk.FlowBox
+	// buttons in recentFlowBox, in gridButtons after the suggested ones
+	recentCount int
+)
+
+// Size of the recently used row, at most one full row, off by default as the
+// suggested row mostly shows the same entries:
+//
+//	[recent]
+//	size = 6 # 0 hides the row
+//	expanded = true
+func recentSize() int {
+	size := cfg.Int("recent", "size", 0)
+	if size > int(columns) {
+		size = int(columns)
+	}
+	return size
+}
+
+// Creates the collapsible "Recently used" row shown above the grid on the
+// empty query
+func newRecentRow() *gtk.Expander {
+	recentExpander, _ = gtk.ExpanderNew("Recently used")
+	recentExpander.SetExpanded(cfg.Bool("recent", "expanded", true))
+	recentExpander.SetNoShowAll(true)
+	recentExpander.Connect("notify::expanded", func() {
+		setUpAppsFlowBox(phrase)
+	})
+
+	recentFlowBox, _ = gtk.FlowBoxNew()
+	recentFlowBox.SetColumnSpacing(settings.Spacing)
+	recentFlowBox.SetHomogeneous(true)
+	recentFlowBox.SetSelectionMode(gtk.SELECTION_NONE)
+	recentFlowBox.SetMinChildrenPerLine(columns)
+	recentFlowBox.SetMaxChildrenPerLine(columns)
+	recentFlowBox.SetHAlign(gtk.ALIGN_CENTER)
+	recentFlowBox.Show()
+	recentExpander.Add(recentFlowBox)
+	return recentExpander
+}
+
+// Fills the recently used row with the entries launched last, going by the
+// history the suggested row ranks
+func setUpRecent(snapshot *entries.Snapshot, searchPhrase string) {
+	recentCount = 0
+	if recentExpander == nil {
+		return
+	}
+
+	var recent []entries.DesktopEntry
+	if searchPhrase == "" && categoryFilter == "" && recentSize() > 0 {
+		recent = history.Recent(snapshot.Entries(), recentSize())
+	}
+	if len(recent) == 0 {
+		recentExpander.Hide()
+		return
+	}
+	recentExpander.Show()
+	if !recentExpander.GetExpanded() {
+		return
+	}
+
+	for _, entry := range recent {
+		recentFlowBox.Add(getAppButton(entry))
+	}
+	recentCount = len(recent)
+	recentFlowBox.GetChildren().Foreach(func(item interface{}) {
+		item.(*gtk.Widget).SetCanFocus(false)
+	})
+	recentFlowBox.ShowAll()
+}
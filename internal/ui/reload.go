@@ -0,0 +1,42 @@
+package ui
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/gotk3/gotk3/glib"
+
+	"github.com/ftphikari/wlaunchpad/internal/config"
+)
+
+// Reload replaces the config with the one load reads again and applies what
+// can change while running: the style, key bindings, providers, the grid's
+// columns, icon size and spacing, and the terminal. The desktop files are
+// scanned again, for merged entries and overrides. Safe to call from any
+// goroutine.
+func Reload(load func() config.Config) {
+	glib.IdleAdd(func() bool {
+		// chosen for the whole run, the window and watchers are set up for them
+		kept := *settings
+		cfg = load()
+		settings.Daemon, settings.NoShow, settings.Backend = kept.Daemon, kept.NoShow, kept.Backend
+		settings.Debug, settings.TV, settings.OSK = kept.Debug, kept.TV, kept.OSK
+		settings.Gamepad, settings.LauncherEntry = kept.Gamepad, kept.LauncherEntry
+		settings.TargetOutput = kept.TargetOutput
+
+		for _, err := range checkProviderConfig() {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", settings.ConfigFile, err)
+			log.Printf("Config error: %s", err)
+		}
+		loadStyle()
+		setUpBindings()
+		status = parseDesktopFiles()
+		if !hibernating {
+			statusLabel.SetText(status)
+			applyGridSettings()
+		}
+		log.Printf("Reloaded %s\n", settings.ConfigFile)
+		return false
+	})
+}
@@ -0,0 +1,86 @@
+package ui
+
+// #cgo pkg-config: gtk+-3.0
+// #include <stdlib.h>
+// #include <gtk/gtk.h>
+//
+// // Returns the file the default icon theme has for name at size, NULL if
+// // there's none
+// static char *lookup_icon(const char *name, int size) {
+// 	GtkIconInfo *info = gtk_icon_theme_lookup_icon(gtk_icon_theme_get_default(), name, size, GTK_ICON_LOOKUP_FORCE_SIZE);
+// 	if (info == NULL)
+// 		return NULL;
+// 	char *filename = g_strdup(gtk_icon_info_get_filename(info));
+// 	g_object_unref(info);
+// 	return filename;
+// }
+import "C"
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"unsafe"
+
+	"github.com/gotk3/gotk3/gtk"
+
+	"github.com/ftphikari/wlaunchpad/internal/entries"
+)
+
+// ResolveIcon returns the file the grid shows for icon at size, looked up the
+// way it does. A desktop ID stands for the icon of its entry, one chosen in
+// its place included.
+func ResolveIcon(icon string, size int) (string, error) {
+	if err := gtk.InitCheck(nil); err != nil {
+		return "", err
+	}
+
+	if strings.HasSuffix(icon, ".desktop") {
+		path, err := entries.FindFile(icon)
+		if err != nil {
+			return "", err
+		}
+		// the ID of files in subdirectories isn't their name
+		entry, err := entries.ParseFile(icon, path)
+		if err != nil {
+			return "", err
+		}
+		entry.ComponentID = entries.ComponentID(entry.DesktopID, entries.ComponentIDs(entries.MetainfoDirs()))
+		loadIconOverrides()
+		icon = entryIcon(entry)
+		if icon == "" {
+			return "", fmt.Errorf("%s has no icon", path)
+		}
+	}
+
+	if strings.Contains(icon, "/") {
+		if _, err := os.Stat(icon); err != nil {
+			return "", err
+		}
+		return icon, nil
+	} else if strings.HasSuffix(icon, ".svg") || strings.HasSuffix(icon, ".png") || strings.HasSuffix(icon, ".xpm") {
+		icon = strings.Split(icon, ".")[0]
+	}
+
+	name := C.CString(icon)
+	defer C.free(unsafe.Pointer(name))
+	filename := C.lookup_icon(name, C.int(size))
+	if filename == nil {
+		return "", fmt.Errorf("%s not found in the %s icon theme, nor its fallbacks", icon, iconThemeName())
+	}
+	defer C.g_free(C.gpointer(filename))
+	return C.GoString(filename), nil
+}
+
+// Returns the name of the icon theme in use
+func iconThemeName() string {
+	gtkSettings, err := gtk.SettingsGetDefault()
+	if err != nil {
+		return "default"
+	}
+	name, err := gtkSettings.GetProperty("gtk-icon-theme-name")
+	if s, ok := name.(string); err == nil && ok && s != "" {
+		return s
+	}
+	return "default"
+}
@@ -0,0 +1,75 @@
+package ui
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/gotk3/gotk3/gtk"
+
+	"github.com/ftphikari/wlaunchpad/internal/config"
+	"github.com/ftphikari/wlaunchpad/internal/entries"
+)
+
+// Fields the search matches, toggled with the chips under the search entry
+// and kept between sessions
+var scope = entries.Fields{Names: true, Comments: true, Keywords: true, Commands: true}
+
+// Format changes of the saved scope, see config.Migrations
+var scopeMigrations = config.Migrations{}
+
+func scopePath() string {
+	return filepath.Join(config.StateDir(), "search-scope")
+}
+
+func loadScope() {
+	saved, err := scopeMigrations.Load(scopePath())
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Printf("Couldn't read %s: %s", scopePath(), err)
+		}
+		return
+	}
+	scope.Names = saved.Bool("", "names", true)
+	scope.Comments = saved.Bool("", "comments", true)
+	scope.Keywords = saved.Bool("", "keywords", true)
+	scope.Commands = saved.Bool("", "commands", true)
+}
+
+func saveScope() {
+	contents := fmt.Sprintf("names = %t\ncomments = %t\nkeywords = %t\ncommands = %t\n",
+		scope.Names, scope.Comments, scope.Keywords, scope.Commands)
+	if err := config.WriteFile(scopePath(), []byte(contents), 0644); err != nil {
+		log.Printf("Couldn't save %s: %s", scopePath(), err)
+	}
+}
+
+// Returns the row of toggle chips
+func scopeChips() *gtk.Box {
+	box, _ := gtk.BoxNew(gtk.ORIENTATION_HORIZONTAL, 4)
+	for _, chip := range []struct {
+		label string
+		field *bool
+	}{
+		{"Names", &scope.Names},
+		{"Comments", &scope.Comments},
+		{"Keywords", &scope.Keywords},
+		{"Commands", &scope.Commands},
+	} {
+		field := chip.field
+		button, _ := gtk.ToggleButtonNewWithLabel(chip.label)
+		button.SetActive(*field)
+		button.SetCanFocus(false)
+		ctx, _ := button.GetStyleContext()
+		ctx.AddClass("scope-chip")
+		button.Connect("toggled", func() {
+			*field = button.GetActive()
+			saveScope()
+			setUpAppsFlowBox(phrase)
+			focusFirstItem()
+		})
+		box.PackStart(button, false, false, 0)
+	}
+	return box
+}
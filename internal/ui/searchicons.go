@@ -0,0 +1,164 @@
+package ui
+
+import (
+	"fmt"
+	"log"
+	"os/exec"
+
+	"github.com/gotk3/gotk3/gtk"
+
+	"github.com/ftphikari/wlaunchpad/internal/entries"
+	"github.com/ftphikari/wlaunchpad/internal/launch"
+)
+
+// Icons of the search entry, the secondary one being the search entry's own
+// clear icon while there's text
+const (
+	categoriesIcon = "view-list-symbolic"
+	settingsIcon   = "emblem-system-symbolic"
+)
+
+// Makes the icons of the search entry controls: the primary one picks the
+// category shown, the secondary one clears the search, or opens the settings
+// when there's nothing to clear
+func setUpSearchIcons() {
+	searchEntry.SetIconFromIconName(gtk.ENTRY_ICON_PRIMARY, categoriesIcon)
+	searchEntry.SetIconTooltipText(gtk.ENTRY_ICON_PRIMARY, "Show a category")
+	searchEntry.SetIconActivatable(gtk.ENTRY_ICON_PRIMARY, true)
+	showSettingsIcon()
+
+	// after the search entry's own handler, which drops the clear icon
+	searchEntry.Connect("changed", func() {
+		if s, _ := searchEntry.GetText(); s == "" {
+			showSettingsIcon()
+		} else {
+			searchEntry.SetIconTooltipText(gtk.ENTRY_ICON_SECONDARY, "Clear the search")
+		}
+	})
+	searchEntry.Connect("icon-press", func(entry *gtk.SearchEntry, position gtk.EntryIconPosition) {
+		switch position {
+		case gtk.ENTRY_ICON_PRIMARY:
+			showCategoryMenu()
+		case gtk.ENTRY_ICON_SECONDARY:
+			// the search entry clears the text itself
+			if s, _ := searchEntry.GetText(); s == "" {
+				showSettings()
+			}
+		}
+	})
+}
+
+func showSettingsIcon() {
+	searchEntry.SetIconFromIconName(gtk.ENTRY_ICON_SECONDARY, settingsIcon)
+	searchEntry.SetIconTooltipText(gtk.ENTRY_ICON_SECONDARY, "Settings")
+	searchEntry.SetIconActivatable(gtk.ENTRY_ICON_SECONDARY, true)
+}
+
+// Returns a popover pointing at an icon of the search entry
+func searchIconPopover(position gtk.EntryIconPosition) *gtk.Popover {
+	popover, _ := gtk.PopoverNew(searchEntry)
+	popover.SetPosition(gtk.POS_BOTTOM)
+	if area := searchEntry.GetIconArea(position); area != nil {
+		popover.SetPointingTo(*area)
+	}
+	popover.Connect("closed", func() {
+		popover.Destroy()
+	})
+	return popover
+}
+
+func popoverBox(popover *gtk.Popover) *gtk.Box {
+	box, _ := gtk.BoxNew(gtk.ORIENTATION_VERTICAL, 4)
+	box.SetMarginStart(10)
+	box.SetMarginEnd(10)
+	box.SetMarginTop(10)
+	box.SetMarginBottom(10)
+	popover.Add(box)
+	return box
+}
+
+// Lists the categories of the installed apps, the one shown checked
+func showCategoryMenu() {
+	popover := searchIconPopover(gtk.ENTRY_ICON_PRIMARY)
+	box := popoverBox(popover)
+
+	all, _ := gtk.RadioButtonNewWithLabel(nil, "All applications")
+	all.SetActive(categoryFilter == "")
+	all.Connect("toggled", func() {
+		if all.GetActive() {
+			popover.Popdown()
+			setCategoryFilter("")
+		}
+	})
+	box.PackStart(all, false, false, 0)
+	for _, category := range entries.Categories(currentEntries().Entries()) {
+		category := category
+		button, _ := gtk.RadioButtonNewWithLabelFromWidget(all, category)
+		button.SetActive(category == categoryFilter)
+		button.Connect("toggled", func() {
+			if button.GetActive() {
+				popover.Popdown()
+				setCategoryFilter(category)
+			}
+		})
+		box.PackStart(button, false, false, 0)
+	}
+
+	box.ShowAll()
+	popover.Popup()
+}
+
+// Shows the settings changeable on the fly: zoom, and the way to the rest
+func showSettings() {
+	popover := searchIconPopover(gtk.ENTRY_ICON_SECONDARY)
+	box := popoverBox(popover)
+
+	zoomRow, _ := gtk.BoxNew(gtk.ORIENTATION_HORIZONTAL, 4)
+	zoomLabel, _ := gtk.LabelNew(fmt.Sprintf("Zoom %d%%", int(zoom*100)))
+	zoomLabel.SetHAlign(gtk.ALIGN_START)
+	zoomRow.PackStart(zoomLabel, true, true, 0)
+	for _, b := range []struct {
+		icon    string
+		tooltip string
+		level   func() float64
+	}{
+		{"zoom-out-symbolic", "Zoom out", func() float64 { return zoom - zoomStep }},
+		{"zoom-original-symbolic", "Reset the zoom", func() float64 { return 1 }},
+		{"zoom-in-symbolic", "Zoom in", func() float64 { return zoom + zoomStep }},
+	} {
+		level := b.level
+		button, _ := gtk.ButtonNewFromIconName(b.icon, gtk.ICON_SIZE_BUTTON)
+		button.SetTooltipText(b.tooltip)
+		button.Connect("clicked", func() {
+			setZoom(level())
+			zoomLabel.SetText(fmt.Sprintf("Zoom %d%%", int(zoom*100)))
+		})
+		zoomRow.PackStart(button, false, false, 0)
+	}
+	box.PackStart(zoomRow, false, false, 0)
+
+	keys, _ := gtk.ModelButtonNew()
+	keys.SetLabel("Keys")
+	keys.Connect("clicked", func() {
+		popover.Popdown()
+		toggleCheatsheet()
+	})
+	box.PackStart(keys, false, false, 0)
+
+	configFile, _ := gtk.ModelButtonNew()
+	configFile.SetLabel("Open the config file")
+	configFile.Connect("clicked", func() {
+		cmd := exec.Command("xdg-open", settings.ConfigFile)
+		launch.Detach(cmd)
+		if err := cmd.Start(); err != nil {
+			log.Printf("Couldn't open %s: %s", settings.ConfigFile, err)
+			return
+		}
+		launch.Reap(cmd)
+		closeWindow()
+	})
+	box.PackStart(configFile, false, false, 0)
+
+	box.ShowAll()
+	popover.Popup()
+}
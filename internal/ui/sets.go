@@ -0,0 +1,105 @@
+package ui
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/gotk3/gotk3/glib"
+	"github.com/gotk3/gotk3/gtk"
+
+	"github.com/ftphikari/wlaunchpad/internal/entries"
+	"github.com/ftphikari/wlaunchpad/internal/launch"
+)
+
+// Returns synthetic entries for the session sets defined in the config file:
+//
+//	[set.work]
+//	name = "Work"
+//	icon = "applications-office"
+//	apps = ["slack.desktop", "org.mozilla.firefox", "code.desktop"] # desktop or component IDs
+//	delay = 1000 # milliseconds between launches
+//
+// Members are launched in the listed order, so an app which depends on
+// another one being up should be listed after it.
+func sessionSetEntries(apps []entries.DesktopEntry) []entries.DesktopEntry {
+	id2entry := make(map[string]entries.DesktopEntry)
+	for _, entry := range apps {
+		id2entry[entry.DesktopID] = entry
+		if _, ok := id2entry[entry.ComponentID]; !ok && entry.ComponentID != "" {
+			id2entry[entry.ComponentID] = entry
+		}
+	}
+
+	var setEntries []entries.DesktopEntry
+	for _, name := range cfg.Subsections("set") {
+		section := "set." + name
+
+		var members []entries.DesktopEntry
+		var names []string
+		for _, id := range cfg.List(section, "apps") {
+			member, ok := id2entry[id]
+			if !ok && !strings.HasSuffix(id, ".desktop") {
+				id += ".desktop"
+				member, ok = id2entry[id]
+			}
+			if !ok {
+				log.Printf("Set %q: %s not found, skipping", name, id)
+				continue
+			}
+			members = append(members, member)
+			names = append(names, member.NameLoc)
+		}
+		if len(members) == 0 {
+			continue
+		}
+
+		delay := time.Duration(cfg.Int(section, "delay", 500)) * time.Millisecond
+		entry := entries.DesktopEntry{
+			DesktopID: "set:" + name,
+			Name:      cfg.Str(section, "name", name),
+			Comment:   fmt.Sprintf("Launches %s", strings.Join(names, ", ")),
+			Icon:      cfg.Str(section, "icon", "system-run"),
+			Category:  "X-Set",
+			Action: func() {
+				launchSet(members, delay)
+			},
+		}
+		entry.NameLoc = entry.Name
+		entry.CommentLoc = entry.Comment
+		setEntries = append(setEntries, entry)
+	}
+	return setEntries
+}
+
+// Launches members one after another, waiting delay between them. In non-daemon
+// mode we only quit once the last member has been started.
+func launchSet(members []entries.DesktopEntry, delay time.Duration) {
+	// one token each, while the window still has focus
+	tokens := make([]string, len(members))
+	for i := range members {
+		tokens[i] = activationToken(members[i])
+	}
+	win.Hide()
+	go func() {
+		for i, member := range members {
+			if i > 0 {
+				time.Sleep(delay)
+			}
+			cmd, err := startCommand(member, tokens[i])
+			if err != nil {
+				// the window is hidden already
+				notifyLaunchFailure(member, err)
+			} else if cmd != nil {
+				launch.Reap(cmd)
+			}
+		}
+		if !settings.Daemon {
+			glib.IdleAdd(func() bool {
+				gtk.MainQuit()
+				return false
+			})
+		}
+	}()
+}
@@ -0,0 +1,121 @@
+package ui
+
+import (
+	"github.com/gotk3/gotk3/glib"
+	"github.com/gotk3/gotk3/gtk"
+
+	"github.com/ftphikari/wlaunchpad/internal/entries"
+)
+
+const skeletonStyle = `
+.skeleton {
+	background-color: alpha(currentColor, 0.1);
+	border-radius: 12px;
+}
+`
+
+// Icons loaded per main loop iteration while deferred
+const iconBatch = 8
+
+var (
+	// the desktop files are being parsed for the first time, the grid shows
+	// placeholders meanwhile
+	scanning bool
+	// icons not cached yet are loaded after the frame is drawn, until the
+	// first full grid has all of them
+	deferIcons   bool
+	pendingIcons []*appButton
+	iconLoader   glib.SourceHandle
+)
+
+// Parses the desktop files in the background, the window shows a skeleton of
+// the grid until they're in
+func scanInBackground() {
+	scanning, deferIcons = true, true
+	go func() {
+		list, invalid, slow := scanDesktopFiles()
+		glib.IdleAdd(func() bool {
+			finishScan(list, invalid, slow)
+			return false
+		})
+	}()
+}
+
+func finishScan(list []entries.DesktopEntry, invalid int, slow []string) {
+	status = storeEntries(list, invalid, slow)
+	// after the scan, which finds the component IDs it's keyed by
+	loadHistory()
+	loadPins()
+	scanning = false
+	if hibernating {
+		return
+	}
+	statusLabel.SetText(status)
+	setUpAppsFlowBox(phrase)
+	if _, _, ok := adaptiveIconSizes(); ok && windowWidth > 0 {
+		// the page was fit to the empty grid
+		relayout(windowWidth, windowHeight)
+	}
+	// as if the phrase typed meanwhile was typed now
+	if phrase != "" {
+		focusFirstItem()
+	} else {
+		focusOnShow()
+	}
+}
+
+// Fills the grid with grey placeholders the size of buttons, a few rows of them
+func setUpSkeleton() {
+	for i := 0; i < 3*int(columns); i++ {
+		cell, _ := gtk.BoxNew(gtk.ORIENTATION_VERTICAL, 0)
+		cell.SetSizeRequest(cellWidth(iconSize), rowHeight(iconSize))
+		ctx, _ := cell.GetStyleContext()
+		ctx.AddClass("skeleton")
+		appFlowBox.Add(cell)
+	}
+}
+
+// Shows the icon of the button's entry, or while icons are deferred a
+// placeholder until loadPendingIcons gets to it
+func (ab *appButton) setIcon() {
+	ctx, _ := ab.image.GetStyleContext()
+	if _, ok := iconCache[entryIcon(ab.entry)]; !ok && deferIcons {
+		ab.image.Clear()
+		ab.image.SetSizeRequest(iconSize, iconSize)
+		ctx.AddClass("skeleton")
+		ab.broken.Hide()
+		pendingIcons = append(pendingIcons, ab)
+		if iconLoader == 0 {
+			iconLoader = glib.IdleAdd(loadPendingIcons)
+		}
+		return
+	}
+
+	ctx.RemoveClass("skeleton")
+	ab.image.SetSizeRequest(-1, -1)
+	if pixbuf := iconPixbuf(entryIcon(ab.entry)); pixbuf != nil {
+		ab.image.SetFromPixbuf(pixbuf)
+	} else {
+		ab.image.Clear()
+	}
+	markBrokenIcon(ab)
+}
+
+// Loads a batch of the deferred icons, replacing the placeholders. Buttons
+// reused for another entry meanwhile got queued again, with their new entry.
+func loadPendingIcons() bool {
+	for i := 0; i < iconBatch && len(pendingIcons) > 0; i++ {
+		ab := pendingIcons[0]
+		pendingIcons = pendingIcons[1:]
+		iconPixbuf(entryIcon(ab.entry))
+		ab.setIcon()
+	}
+	if len(pendingIcons) > 0 {
+		return true
+	}
+	iconLoader = 0
+	if !scanning {
+		deferIcons = false
+	}
+	return false
+}
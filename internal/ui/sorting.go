@@ -0,0 +1,82 @@
+package ui
+
+import (
+	"log"
+	"time"
+
+	"github.com/ftphikari/wlaunchpad/internal/entries"
+)
+
+// Returns the sort strategy of the grid for the search phrase and category
+// filter in effect, from the config file:
+//
+//	[sort]
+//	all = "alphabetical" # also "frecency", "manual" and "category"
+//	search = "score"     # by where the phrase was found, then frecency (default), search only
+//	category = "frecency" # grid filtered to a category
+//	manual = ["firefox.desktop", "foot.desktop"] # the rest follow by name
+//
+//	[sort.categories]
+//	Game = "manual" # overrides category for one
+func gridSorter(searchPhrase string, phrases []string) entries.Sorter {
+	view := "all"
+	if searchPhrase != "" {
+		view = "search"
+	} else if categoryFilter != "" {
+		view = "category"
+	}
+	fallback := "alphabetical"
+	if view == "search" {
+		// names first, keywords after
+		fallback = "score"
+	}
+	name := cfg.Str("sort", view, fallback)
+	if view == "category" && cfg.Has("sort.categories", categoryFilter) {
+		name = cfg.Str("sort.categories", categoryFilter, name)
+	}
+
+	switch name {
+	case "alphabetical":
+		return entries.Alphabetical{}
+	case "frecency":
+		return entries.ByFrecency{Ranker: ranking(), Now: time.Now()}
+	case "manual":
+		return entries.Manual{Order: cfg.List("sort", "manual")}
+	case "category":
+		return entries.ByCategory{}
+	case "score":
+		if view == "search" {
+			return entries.ByScore{
+				Score: func(entry entries.DesktopEntry) int {
+					return matchScore(entry, phrases)
+				},
+				// "fi" finds Firefox and Files equally well
+				Ranker: ranking(),
+				Now:    time.Now(),
+			}
+		}
+	}
+	log.Printf("Unknown sort strategy %q for %s, sorting alphabetically", name, view)
+	return entries.Alphabetical{}
+}
+
+// Fields in the order matches in them rank, the order Fields.Find looks in
+var scoredFields = []string{"Name", "GenericName", "Keywords", "Comment", "Exec"}
+
+// Ranks matches in names over the other fields, and nearer to the start of
+// the field first. Fuzzy matches come after, closest first.
+func matchScore(entry entries.DesktopEntry, phrases []string) int {
+	match, ok := scope.Find(entry, phrases)
+	if !ok {
+		return (len(scoredFields) + 1) << 16
+	}
+	if match.Fuzzy {
+		return len(scoredFields)<<16 + match.Gaps
+	}
+	for i, field := range scoredFields {
+		if field == match.Field {
+			return i<<16 + match.Pos
+		}
+	}
+	return len(scoredFields) << 16
+}
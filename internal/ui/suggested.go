@@ -0,0 +1,151 @@
+package ui
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gotk3/gotk3/gtk"
+
+	"github.com/ftphikari/wlaunchpad/internal/config"
+	"github.com/ftphikari/wlaunchpad/internal/entries"
+)
+
+var (
+	history           entries.History
+	externalScores    entries.Scores
+	suggestedExpander *gtk.Expander
+	suggestedFlowBox  *gtk.FlowBox
+	// buttons in suggestedFlowBox, in gridButtons after the pinned ones
+	suggestedCount int
+)
+
+func historyPath() string {
+	return filepath.Join(config.StateDir(), "history")
+}
+
+// Usage data shared with other launchers, in the config file:
+//
+//	[usage]
+//	export = "$HOME/.local/state/launches.tsv" # "<unix time>\t<desktop ID>" appended per launch
+//	import = "$HOME/.cache/other/scores"       # "<score> <desktop ID>" lines ranked with ours
+func usagePath(key string) string {
+	return os.ExpandEnv(cfg.Str("usage", key, ""))
+}
+
+func loadHistory() {
+	var err error
+	history, err = entries.LoadHistory(historyPath())
+	if err != nil {
+		log.Printf("Couldn't read %s: %s", historyPath(), err)
+	}
+	// launches saved before apps had a component ID
+	if history.Rekey(entries.StateIDs(currentEntries().Entries())) {
+		if err := history.Save(historyPath()); err != nil {
+			log.Printf("Couldn't save %s: %s", historyPath(), err)
+		}
+	}
+	loadExternalScores()
+}
+
+// Reads the imported scores again, other launchers keep updating them
+func loadExternalScores() {
+	path := usagePath("import")
+	if path == "" {
+		return
+	}
+	var err error
+	externalScores, err = entries.LoadScores(path)
+	if err != nil {
+		log.Printf("Couldn't read %s: %s", path, err)
+	}
+	// other launchers go by desktop IDs
+	externalScores.Rekey(entries.StateIDs(currentEntries().Entries()))
+}
+
+// Returns our history ranked together with the imported scores
+func ranking() entries.Ranker {
+	return entries.Ranking{History: history, External: externalScores}
+}
+
+func recordLaunch(entry entries.DesktopEntry) {
+	now := time.Now()
+	if path := usagePath("export"); path != "" {
+		if err := entries.AppendUsage(path, entry.DesktopID, now); err != nil {
+			log.Printf("Couldn't append to %s: %s", path, err)
+		}
+	}
+	if history == nil {
+		return
+	}
+	history.Record(entry.StateID(), now)
+	if err := history.Save(historyPath()); err != nil {
+		log.Printf("Couldn't save %s: %s", historyPath(), err)
+	}
+}
+
+// Size of the suggested row, at most one full row:
+//
+//	[suggested]
+//	size = 4 # 0 hides the row
+//	expanded = false
+func suggestedSize() int {
+	size := cfg.Int("suggested", "size", int(columns))
+	if size > int(columns) {
+		size = int(columns)
+	}
+	return size
+}
+
+// Creates the collapsible "Suggested" row shown above the grid on the empty
+// query
+func newSuggestedRow() *gtk.Expander {
+	suggestedExpander, _ = gtk.ExpanderNew("Suggested")
+	suggestedExpander.SetExpanded(cfg.Bool("suggested", "expanded", true))
+	suggestedExpander.SetNoShowAll(true)
+	suggestedExpander.Connect("notify::expanded", func() {
+		setUpAppsFlowBox(phrase)
+	})
+
+	suggestedFlowBox, _ = gtk.FlowBoxNew()
+	suggestedFlowBox.SetColumnSpacing(settings.Spacing)
+	suggestedFlowBox.SetHomogeneous(true)
+	suggestedFlowBox.SetSelectionMode(gtk.SELECTION_NONE)
+	suggestedFlowBox.SetMinChildrenPerLine(columns)
+	suggestedFlowBox.SetMaxChildrenPerLine(columns)
+	suggestedFlowBox.SetHAlign(gtk.ALIGN_CENTER)
+	suggestedFlowBox.Show()
+	suggestedExpander.Add(suggestedFlowBox)
+	return suggestedExpander
+}
+
+// Fills the suggested row, the first buttons added to the grid
+func setUpSuggested(snapshot *entries.Snapshot, searchPhrase string) {
+	suggestedCount = 0
+	if suggestedExpander == nil {
+		return
+	}
+
+	var suggested []entries.DesktopEntry
+	if searchPhrase == "" && categoryFilter == "" && suggestedSize() > 0 {
+		suggested = entries.Top(ranking(), snapshot.Entries(), suggestedSize(), time.Now())
+	}
+	if len(suggested) == 0 {
+		suggestedExpander.Hide()
+		return
+	}
+	suggestedExpander.Show()
+	if !suggestedExpander.GetExpanded() {
+		return
+	}
+
+	for _, entry := range suggested {
+		suggestedFlowBox.Add(getAppButton(entry))
+	}
+	suggestedCount = len(suggested)
+	suggestedFlowBox.GetChildren().Foreach(func(item interface{}) {
+		item.(*gtk.Widget).SetCanFocus(false)
+	})
+	suggestedFlowBox.ShowAll()
+}
@@ -0,0 +1,106 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/ftphikari/wlaunchpad/internal/config"
+)
+
+// CSS the theme variables are put into. The colors are defined as
+// @wl_background, @wl_foreground and @wl_accent for -style files too.
+const themeTemplate = `
+@define-color wl_background ${background};
+@define-color wl_foreground ${foreground};
+@define-color wl_accent ${accent};
+
+window {
+	background-color: @wl_background;
+	color: @wl_foreground;
+}
+window button {
+	border-radius: ${radius}px;
+	padding: ${padding}px;
+}
+window button:hover, window button:focus {
+	background-color: alpha(@wl_accent, 0.3);
+}
+window entry {
+	border-radius: ${radius}px;
+}
+window entry:focus {
+	border-color: @wl_accent;
+}
+window .suggested-action {
+	background-color: @wl_accent;
+}
+`
+
+// Theme variables and their defaults
+var themeDefaults = map[string]string{
+	"background": "rgba(30, 30, 30, 0.9)",
+	"foreground": "#eeeeee",
+	"accent":     "#3584e4",
+	"radius":     "8",
+	"padding":    "6",
+}
+
+// ThemePath returns the path of the theme variables file
+func ThemePath() string {
+	return filepath.Join(config.Dir(), "theme.toml")
+}
+
+// Returns the CSS of the theme file, "" without one
+func loadTheme() (string, error) {
+	theme, err := config.Migrations{}.Load(ThemePath())
+	if os.IsNotExist(err) {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+	return themeCSS(theme)
+}
+
+// Puts the theme variables, or their defaults, into themeTemplate:
+//
+//	background = "#1e1e2e" # any GTK CSS color
+//	foreground = "#cdd6f4"
+//	accent = "#89b4fa"
+//	radius = 12  # of buttons and the search, in pixels
+//	padding = 8  # around buttons' icons and names, in pixels
+func themeCSS(theme config.Config) (string, error) {
+	for _, key := range theme.Keys("") {
+		if _, ok := themeDefaults[key]; !ok {
+			return "", fmt.Errorf("unknown variable %q", key)
+		}
+	}
+	values := make(map[string]string)
+	for key, fallback := range themeDefaults {
+		value := theme.Str("", key, fallback)
+		switch key {
+		case "radius", "padding":
+			if n, err := strconv.Atoi(value); err != nil || n < 0 {
+				return "", fmt.Errorf("%s: expected a number of pixels, got %q", key, value)
+			}
+		default:
+			// would end the declaration and let anything follow
+			if value == "" || strings.ContainsAny(value, ";{}") {
+				return "", fmt.Errorf("%s: invalid color %q", key, value)
+			}
+		}
+		values[key] = value
+	}
+	return os.Expand(themeTemplate, func(key string) string {
+		return values[key]
+	}), nil
+}
+
+// CheckTheme returns what's wrong with the theme variables file, nil if
+// nothing or there's none
+func CheckTheme() error {
+	_, err := loadTheme()
+	return err
+}
@@ -0,0 +1,39 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ftphikari/wlaunchpad/internal/config"
+)
+
+func TestThemeCSS(t *testing.T) {
+	theme, _ := config.Parse(strings.NewReader("accent = \"#89b4fa\"\nradius = 12\n"))
+	css, err := themeCSS(theme)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"@define-color wl_accent #89b4fa;",
+		"@define-color wl_foreground #eeeeee;", // the default
+		"border-radius: 12px;",
+	} {
+		if !strings.Contains(css, want) {
+			t.Errorf("expected %q in:\n%s", want, css)
+		}
+	}
+	if strings.Contains(css, "$") {
+		t.Errorf("variables left in:\n%s", css)
+	}
+
+	for _, invalid := range []string{
+		"radius = \"round\"\n",
+		"accent = \"red; } * { color: red\"\n",
+		"acent = \"red\"\n",
+	} {
+		theme, _ := config.Parse(strings.NewReader(invalid))
+		if _, err := themeCSS(theme); err == nil {
+			t.Errorf("%q: expected an error", invalid)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package ui
+
+import (
+	"github.com/gotk3/gotk3/glib"
+	"github.com/gotk3/gotk3/gtk"
+)
+
+// How long a toast stays, in milliseconds
+const toastTimeout = 8000
+
+var (
+	toastRevealer *gtk.Revealer
+	toastLabel    *gtk.Label
+	toastButton   *gtk.Button
+	toastAction   func()
+	toastTimer    glib.SourceHandle
+)
+
+// Creates the toast area, to be packed above the status line
+func newToast() *gtk.Revealer {
+	toastRevealer, _ = gtk.RevealerNew()
+	toastRevealer.SetTransitionType(gtk.REVEALER_TRANSITION_TYPE_SLIDE_UP)
+
+	box, _ := gtk.BoxNew(gtk.ORIENTATION_HORIZONTAL, 10)
+	box.SetHAlign(gtk.ALIGN_CENTER)
+	ctx, _ := box.GetStyleContext()
+	ctx.AddClass("toast")
+	toastRevealer.Add(box)
+
+	toastLabel, _ = gtk.LabelNew("")
+	box.PackStart(toastLabel, false, false, 0)
+	toastButton, _ = gtk.ButtonNew()
+	toastButton.Connect("clicked", func() {
+		action := toastAction
+		hideToast()
+		if action != nil {
+			action()
+		}
+	})
+	box.PackStart(toastButton, false, false, 0)
+	return toastRevealer
+}
+
+// Shows a message with a button running action, e.g. "Undo". Without a label
+// there's no button.
+func showToast(text, actionLabel string, action func()) {
+	if toastTimer != 0 {
+		glib.SourceRemove(toastTimer)
+	}
+	toastLabel.SetText(text)
+	toastButton.SetLabel(actionLabel)
+	toastAction = action
+	toastRevealer.ShowAll()
+	if actionLabel == "" {
+		toastButton.Hide()
+	}
+	toastRevealer.SetRevealChild(true)
+	toastTimer = glib.TimeoutAdd(toastTimeout, func() bool {
+		toastTimer = 0
+		hideToast()
+		return false
+	})
+}
+
+func hideToast() {
+	toastAction = nil
+	toastRevealer.SetRevealChild(false)
+}
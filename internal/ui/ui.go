@@ -0,0 +1,405 @@
+// Package ui is the GTK launcher window.
+package ui
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/gotk3/gotk3/gdk"
+	"github.com/gotk3/gotk3/glib"
+	"github.com/gotk3/gotk3/gtk"
+
+	"github.com/ftphikari/wlaunchpad/internal/config"
+	"github.com/ftphikari/wlaunchpad/internal/gamepad"
+	"github.com/ftphikari/wlaunchpad/internal/ipc"
+	"github.com/ftphikari/wlaunchpad/internal/launch"
+)
+
+// UI elements
+var (
+	win                    *gtk.Window
+	resultWindow           *gtk.ScrolledWindow
+	searchEntry            *gtk.SearchEntry
+	phrase                 string
+	iconTheme              *gtk.IconTheme
+	themeStyle             *gtk.CssProvider
+	userStyle              *gtk.CssProvider
+	appFlowBox             *gtk.FlowBox
+	appSearchResultWrapper *gtk.Box
+	statusLabel            *gtk.Label
+	status                 string
+	iconCache              = make(map[string]*gdk.Pixbuf)
+	badgeCounts            map[string]int
+)
+
+var (
+	settings *config.Settings
+	cfg      config.Config
+)
+
+const badgeStyle = `
+.badge {
+	background-color: #e0352b;
+	color: #ffffff;
+	border-radius: 10px;
+	padding: 0 6px;
+	font-weight: bold;
+}
+`
+
+const scopeStyle = `
+.scope-chip {
+	padding: 0 8px;
+	min-height: 0;
+	border-radius: 12px;
+	font-size: 85%;
+}
+`
+
+const toastStyle = `
+.toast {
+	background-color: alpha(#000000, 0.7);
+	color: #ffffff;
+	border-radius: 6px;
+	padding: 4px 12px;
+}
+`
+
+const tvStyle = `
+.tv button label, .tv entry, .tv label {
+	font-size: 150%;
+}
+`
+
+// Init creates the window, showing it unless the daemon was asked not to
+func Init(s *config.Settings, c config.Config) {
+	settings = s
+	cfg = c
+	for _, err := range checkProviderConfig() {
+		fmt.Fprintf(os.Stderr, "%s: %s\n", settings.ConfigFile, err)
+		log.Printf("Config error: %s", err)
+	}
+	restoreBackend := detectBackend()
+	gtk.Init(nil)
+	restoreBackend()
+	loadZoom()
+	setUpBindings()
+	columns, iconSize = settings.Columns, zoomedIconSize()
+	gpus = launch.ProbeGPUs("/sys")
+	log.Printf("GPUs: %+v", gpus)
+
+	builtinStyle := scopeStyle + badgeStyle + brokenIconStyle + toastStyle + skeletonStyle
+	if settings.TV {
+		builtinStyle += tvStyle
+	}
+	// Lower priority than the user style, so it can be overridden with -style
+	builtinProvider, _ := gtk.CssProviderNew()
+	builtinProvider.LoadFromData(builtinStyle)
+	screen, _ := gdk.ScreenGetDefault()
+	gtk.AddProviderForScreen(screen, builtinProvider, gtk.STYLE_PROVIDER_PRIORITY_SETTINGS)
+
+	loadStyle()
+
+	var err error
+	iconTheme, err = gtk.IconThemeGetDefault()
+	if err != nil {
+		log.Fatal("Couldn't get default theme: ", err)
+	}
+
+	loadScope()
+	if settings.Badges {
+		badgeCounts = ipc.NotificationCounts()
+	}
+	// the window comes up right away, the grid fills in once parsed
+	scanInBackground()
+	if settings.LauncherEntry {
+		watchLauncherEntries()
+	}
+	if settings.Daemon && newAppsEnabled() {
+		watchNewApps()
+	}
+	if settings.Gamepad {
+		gamepad.Watch(func(ev gamepad.Event) {
+			glib.IdleAdd(func() bool {
+				handleGamepad(ev)
+				return false
+			})
+		})
+	}
+
+	buildWindow()
+	if wayland() {
+		// We want to assign layershell to a monitor, but we only know the output name!
+		placeOnTargetOutput()
+	}
+	checkForUpdates()
+
+	if !settings.Daemon || !settings.NoShow {
+		focusOnShow()
+		win.ShowAll()
+	}
+}
+
+// Applies the theme variables file and the style given with -style, in place of
+// the ones applied before
+func loadStyle() {
+	screen, _ := gdk.ScreenGetDefault()
+	for _, provider := range []*gtk.CssProvider{themeStyle, userStyle} {
+		if provider != nil {
+			gtk.RemoveProviderForScreen(screen, provider)
+		}
+	}
+	themeStyle, userStyle = nil, nil
+
+	if css, err := loadTheme(); err != nil {
+		log.Printf("ERROR: %s erroneous: %s\n", ThemePath(), err)
+	} else if css != "" {
+		themeStyle, _ = gtk.CssProviderNew()
+		if err := themeStyle.LoadFromData(css); err != nil {
+			log.Printf("ERROR: %s erroneous: %s\n", ThemePath(), err)
+		}
+		// over the builtin style, under -style
+		gtk.AddProviderForScreen(screen, themeStyle, gtk.STYLE_PROVIDER_PRIORITY_SETTINGS+100)
+	}
+
+	if settings.StyleFile == "" {
+		return
+	}
+	cssProvider, _ := gtk.CssProviderNew()
+	err := cssProvider.LoadFromPath(settings.StyleFile)
+	if err != nil {
+		log.Printf("ERROR: %s css file not found or erroneous. Using GTK styling.\n", settings.StyleFile)
+		log.Printf("%s\n", err)
+		return
+	}
+	log.Printf("Using style from %s\n", settings.StyleFile)
+	gtk.AddProviderForScreen(screen, cssProvider, gtk.STYLE_PROVIDER_PRIORITY_APPLICATION)
+	userStyle = cssProvider
+}
+
+// Creates the window and everything in it, from the data loaded by Init. Run
+// again to wake up from hibernation.
+func buildWindow() {
+	var err error
+	win, err = gtk.WindowNew(gtk.WINDOW_TOPLEVEL)
+	if err != nil {
+		log.Fatal("Unable to create window:", err)
+	}
+	if settings.TV {
+		ctx, _ := win.GetStyleContext()
+		ctx.AddClass("tv")
+	}
+
+	if wayland() {
+		initLayerShell()
+		if knownOutputs != nil {
+			setTargetMonitor()
+		}
+	}
+
+	win.Connect("destroy", func() {
+		if hibernating {
+			return
+		}
+		if settings.Daemon {
+			win.Hide()
+		} else {
+			gtk.MainQuit()
+		}
+	})
+	win.Connect("button-press-event", func(window *gtk.Window, event *gdk.Event) bool {
+		if gdk.EventButtonNewFromEvent(event).Button() == 2 {
+			pastePrimary()
+			return true
+		}
+		return false
+	})
+	win.Connect("hide", func() {
+		revealDesktop(false)
+		scheduleHibernation()
+	})
+	win.Connect("show", func() {
+		cancelHibernation()
+		if revealOnShow() {
+			revealDesktop(true)
+		}
+	})
+
+	// the output may change resolution or scale, or the window may be moved
+	// to another output while hidden in daemon mode
+	win.Connect("configure-event", func(window *gtk.Window, event *gdk.Event) bool {
+		configure := gdk.EventConfigureNewFromEvent(event)
+		width, height := configure.Width(), configure.Height()
+		glib.IdleAdd(func() {
+			relayout(width, height)
+		})
+		return false
+	})
+
+	win.Connect("key-press-event", func(window *gtk.Window, event *gdk.Event) bool {
+		key := &gdk.EventKey{Event: event}
+		if composing {
+			return false
+		}
+		return handleBinding(key) || handleEditingKey(key) || handleWindowKey(key)
+	})
+
+	/*
+		In case someone REALLY needed to use X11 - for some stupid Zoom meeting or something, this allows
+		the drawer to behave properly on Openbox, and possibly somewhere else. For sure not on i3.
+		This feature is not really supported and will stay undocumented.
+	*/
+	if !wayland() {
+		log.Println("Not Wayland, oh really?")
+		win.SetDecorated(false)
+		win.Maximize()
+	}
+
+	outerVBox, _ := gtk.BoxNew(gtk.ORIENTATION_VERTICAL, 0)
+	win.Add(outerVBox)
+
+	searchBoxWrapper, _ := gtk.BoxNew(gtk.ORIENTATION_HORIZONTAL, 0)
+	outerVBox.PackStart(searchBoxWrapper, false, false, 10)
+
+	searchEntry, _ = gtk.SearchEntryNew()
+	searchEntry.SetPlaceholderText("Type to search")
+	searchEntry.Connect("search-changed", func() {
+		phrase, _ = searchEntry.GetText()
+		if len(phrase) > 0 {
+			setUpAppsFlowBox(phrase)
+			focusFirstItem()
+		} else {
+			setUpAppsFlowBox("")
+			focusOnShow()
+		}
+	})
+	searchEntry.SetMaxWidthChars(30)
+	setUpSearchIcons()
+	scopeWrapper, _ := gtk.BoxNew(gtk.ORIENTATION_HORIZONTAL, 0)
+	scopeWrapper.PackStart(scopeChips(), true, false, 0)
+	outerVBox.PackStart(scopeWrapper, false, false, 0)
+	outerVBox.PackStart(setUpIME(), false, false, 0)
+
+	if settings.OSK {
+		searchBox, _ := gtk.BoxNew(gtk.ORIENTATION_HORIZONTAL, 6)
+		searchBox.PackStart(searchEntry, false, false, 0)
+		searchBox.PackStart(oskButton(), false, false, 0)
+		searchBoxWrapper.PackStart(searchBox, true, false, 0)
+	} else {
+		searchBoxWrapper.PackStart(searchEntry, true, false, 0)
+	}
+
+	resultWindow, _ = gtk.ScrolledWindowNew(nil, nil)
+	resultWindow.SetEvents(int(gdk.ALL_EVENTS_MASK))
+	resultWindow.SetPolicy(gtk.POLICY_AUTOMATIC, gtk.POLICY_AUTOMATIC)
+	// before the scrolled window scrolls
+	resultWindow.Connect("scroll-event", func(window *gtk.ScrolledWindow, event *gdk.Event) bool {
+		return handleZoomScroll(gdk.EventScrollNewFromEvent(event))
+	})
+	gridRow, _ := gtk.BoxNew(gtk.ORIENTATION_HORIZONTAL, 0)
+	gridRow.PackStart(resultWindow, true, true, 0)
+	gridRow.PackEnd(newPreviewPane(), false, false, 0)
+	outerVBox.PackStart(gridRow, true, true, 10)
+
+	resultsWrapper, _ := gtk.BoxNew(gtk.ORIENTATION_VERTICAL, 0)
+	resultWindow.Add(resultsWrapper)
+
+	appSearchResultWrapper, _ = gtk.BoxNew(gtk.ORIENTATION_VERTICAL, 0)
+	resultsWrapper.PackStart(appSearchResultWrapper, false, false, 0)
+
+	appSearchResultWrapper.PackStart(newPinnedRow(), false, false, 0)
+	appSearchResultWrapper.PackStart(newSuggestedRow(), false, false, 0)
+	appSearchResultWrapper.PackStart(newRecentRow(), false, false, 0)
+
+	setUpAppsFlowBox("")
+
+	hWrapper, _ := gtk.BoxNew(gtk.ORIENTATION_HORIZONTAL, 0)
+	appSearchResultWrapper.PackStart(hWrapper, false, false, 0)
+	hWrapper.PackStart(appFlowBox, true, false, 0)
+
+	placeholder, _ := gtk.BoxNew(gtk.ORIENTATION_VERTICAL, 0)
+	resultsWrapper.PackStart(placeholder, true, true, 0)
+	placeholder.SetSizeRequest(20, 20)
+
+	outerVBox.PackStart(newToast(), false, false, 0)
+
+	statusLineWrapper, _ := gtk.BoxNew(gtk.ORIENTATION_HORIZONTAL, 0)
+	outerVBox.PackStart(statusLineWrapper, false, false, 10)
+	statusLabel, _ = gtk.LabelNew(status)
+	statusLineWrapper.PackStart(statusLabel, true, false, 0)
+	// a different font, text scaling or DPI changes the room labels need
+	statusLabel.Connect("style-updated", func() {
+		if measureText() {
+			forceRelayout()
+		}
+	})
+	setUpUpdateNote(statusLineWrapper)
+	setUpDrop()
+}
+
+// Main runs the GTK main loop until Quit
+func Main() {
+	gtk.Main()
+	restoreDesktopNow()
+}
+
+// Quit stops the GTK main loop
+func Quit() {
+	gtk.MainQuit()
+}
+
+// Toggle shows the window if hidden and hides it otherwise. Safe to call from
+// any goroutine.
+func Toggle() {
+	glib.IdleAdd(func() bool {
+		toggleWindow()
+		return false
+	})
+}
+
+func toggleWindow() {
+	if win.GetVisible() {
+		win.Hide()
+	} else {
+		showWindow()
+	}
+}
+
+// Handle carries out a request from another instance or a wlaunchpad:// URL,
+// returns the answer to queries. Safe to call from any goroutine.
+func Handle(req ipc.Request) string {
+	if req.Action == "warnings" {
+		return currentWarnings().String()
+	}
+	glib.IdleAdd(func() bool {
+		switch req.Action {
+		case "show":
+			if !win.GetVisible() {
+				showWindow()
+			}
+			if category := req.Args.Get("category"); category != "" {
+				setCategoryFilter(category)
+			}
+			if q := req.Args.Get("q"); q != "" {
+				searchEntry.SetText(q)
+				searchEntry.GrabFocusWithoutSelecting()
+				searchEntry.SetPosition(-1)
+			}
+		case "hide":
+			closeWindow()
+		case "toggle":
+			toggleWindow()
+		case "style":
+			loadStyle()
+		case "configure":
+			wake()
+			applySettings(req.Args)
+			if !win.GetVisible() {
+				showWindow()
+			}
+		}
+		return false
+	})
+	return ""
+}
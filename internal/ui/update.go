@@ -0,0 +1,112 @@
+//go:build !noupdatecheck
+// +build !noupdatecheck
+
+package ui
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/gotk3/gotk3/glib"
+	"github.com/gotk3/gotk3/gtk"
+
+	"github.com/ftphikari/wlaunchpad/internal/config"
+	"github.com/ftphikari/wlaunchpad/internal/launch"
+	"github.com/ftphikari/wlaunchpad/internal/update"
+)
+
+func init() {
+	config.AddFeature("network", "update-check")
+}
+
+// How long the release feed may take to answer
+const updateTimeout = 10 * time.Second
+
+var (
+	updateLink     *gtk.LinkButton
+	updateChecking bool
+)
+
+func updateStatePath() string {
+	return filepath.Join(config.StateDir(), "update")
+}
+
+// Adds the note about a newer version to the status line, hidden until there
+// is one
+func setUpUpdateNote(statusLine *gtk.Box) {
+	updateLink, _ = gtk.LinkButtonNewWithLabel(update.FeedURL, "")
+	updateLink.SetRelief(gtk.RELIEF_NONE)
+	updateLink.SetNoShowAll(true)
+	updateLink.Connect("activate-link", func() bool {
+		cmd := exec.Command("xdg-open", updateLink.GetUri())
+		launch.Detach(cmd)
+		if err := cmd.Start(); err != nil {
+			log.Print(err)
+			return true
+		}
+		launch.Reap(cmd)
+		closeWindow()
+		return true
+	})
+	statusLine.PackEnd(updateLink, false, false, 0)
+}
+
+// Asks the release feed for a newer version, at most once a day, if enabled in
+// the config file:
+//
+//	[update]
+//	check = true
+//
+// Building with -tags noupdatecheck leaves the check out.
+func checkForUpdates() {
+	if !cfg.Bool("update", "check", false) || updateChecking {
+		return
+	}
+	state, err := update.LoadState(updateStatePath())
+	if err != nil {
+		log.Printf("Couldn't read %s: %s", updateStatePath(), err)
+	}
+	if !state.Due(time.Now()) {
+		showUpdateNote(state.Latest)
+		return
+	}
+
+	updateChecking = true
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), updateTimeout)
+		defer cancel()
+		release, err := update.Latest(ctx, update.FeedURL)
+		glib.IdleAdd(func() bool {
+			updateChecking = false
+			// a failed check waits for the next day too, the feed isn't polled
+			state.Checked = time.Now()
+			if err != nil {
+				log.Printf("Couldn't check for updates: %s", err)
+			} else {
+				state.Latest = release
+			}
+			if err := state.Save(updateStatePath()); err != nil {
+				log.Printf("Couldn't save %s: %s", updateStatePath(), err)
+			}
+			showUpdateNote(state.Latest)
+			return false
+		})
+	}()
+}
+
+func showUpdateNote(latest update.Release) {
+	if !update.Newer(latest.Version, config.Version) {
+		updateLink.Hide()
+		return
+	}
+	log.Printf("%s is available, running %s", latest.Version, config.Version)
+	updateLink.SetLabel(fmt.Sprintf("%s available", latest.Version))
+	if latest.URL != "" {
+		updateLink.SetUri(latest.URL)
+	}
+	updateLink.Show()
+}
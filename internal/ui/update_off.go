@@ -0,0 +1,12 @@
+//go:build noupdatecheck
+// +build noupdatecheck
+
+package ui
+
+import "github.com/gotk3/gotk3/gtk"
+
+// Built without the update check
+
+func setUpUpdateNote(statusLine *gtk.Box) {}
+
+func checkForUpdates() {}
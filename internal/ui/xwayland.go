@@ -0,0 +1,97 @@
+package ui
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/gotk3/gotk3/glib"
+	"github.com/gotk3/gotk3/gtk"
+
+	"github.com/ftphikari/wlaunchpad/internal/entries"
+	"github.com/ftphikari/wlaunchpad/internal/launch"
+)
+
+// Desktop ID -> whether the app was last seen running under XWayland
+var xwaylandApps = make(map[string]bool)
+
+// Time for a launched app to map its first window
+const xwaylandCheckDelay = 5 * time.Second
+
+// Looks up the windows of a launched app, to tell whether it runs under
+// XWayland. Only the daemon stays around long enough.
+func detectXWayland(id string, pid int) {
+	go func() {
+		time.Sleep(xwaylandCheckDelay)
+
+		shell, err := windowShellOf(pid)
+		if err != nil {
+			log.Printf("Couldn't check for XWayland: %s", err)
+			return
+		}
+		if shell == "" {
+			return
+		}
+		log.Printf("%s runs under %s\n", id, shell)
+		glib.IdleAdd(func() bool {
+			xwaylandApps[id] = shell == "xwayland"
+			return false
+		})
+	}()
+}
+
+// Explains the "X11" badge of apps seen running under XWayland
+func markXWayland(ab *appButton) {
+	if xwaylandApps[ab.entry.DesktopID] {
+		ab.SetTooltipText("Runs under XWayland, right click for Wayland hints")
+	} else {
+		ab.SetTooltipText("")
+	}
+}
+
+// Shows how the app could be made to use Wayland natively, in a popover over
+// its button, with a button writing them into overrides.toml
+func showWaylandHints(ab *appButton) {
+	entry := ab.entry
+	popover, _ := gtk.PopoverNew(ab)
+	popover.SetPosition(gtk.POS_BOTTOM)
+
+	box, _ := gtk.BoxNew(gtk.ORIENTATION_VERTICAL, 6)
+	box.SetMarginStart(10)
+	box.SetMarginEnd(10)
+	box.SetMarginTop(10)
+	box.SetMarginBottom(10)
+	popover.Add(box)
+
+	title, _ := gtk.LabelNew(fmt.Sprintf("%s runs under XWayland", entry.NameLoc))
+	box.PackStart(title, false, false, 0)
+	hints := launch.WaylandHints(entry.Exec)
+	hintsLabel, _ := gtk.LabelNew("Try adding to its Exec line:\n" + strings.Join(hints, "\n"))
+	hintsLabel.SetSelectable(true)
+	ctx, _ := hintsLabel.GetStyleContext()
+	ctx.AddClass("dim-label")
+	box.PackStart(hintsLabel, false, false, 0)
+
+	useButton, _ := gtk.ButtonNewWithLabel("Add to overrides.toml")
+	useButton.SetHAlign(gtk.ALIGN_END)
+	useButton.Connect("clicked", func() {
+		popover.Popdown()
+		command := launch.WithHints(entry.Exec, hints)
+		if err := entries.SetOverride(overridesPath(), entry.DesktopID, "exec", command); err != nil {
+			log.Printf("Couldn't write the Wayland hints of %s: %s", entry.DesktopID, err)
+			statusLabel.SetText(fmt.Sprintf("Couldn't write the Wayland hints of %s: %s", entry.NameLoc, err))
+			return
+		}
+		log.Printf("Exec of %s set to %s\n", entry.DesktopID, command)
+		delete(xwaylandApps, entry.DesktopID)
+		rescan()
+	})
+	box.PackStart(useButton, false, false, 0)
+
+	popover.Connect("closed", func() {
+		popover.Destroy()
+	})
+	box.ShowAll()
+	popover.Popup()
+}
@@ -0,0 +1,11 @@
+//go:build nosway
+// +build nosway
+
+package ui
+
+import "errors"
+
+// Without sway IPC there's no telling which shell a window uses
+func windowShellOf(pid int) (string, error) {
+	return "", errors.New("built without sway support")
+}
@@ -0,0 +1,57 @@
+//go:build !nosway
+// +build !nosway
+
+package ui
+
+import (
+	"context"
+
+	"github.com/joshuarubin/go-sway"
+
+	"github.com/ftphikari/wlaunchpad/internal/config"
+	"github.com/ftphikari/wlaunchpad/internal/launch"
+)
+
+func init() {
+	config.AddFeature("ipc", "sway")
+}
+
+// Calls f with a fresh sway client, retrying
+func withSway(f func(ctx context.Context, client sway.Client) error) error {
+	return withRetries(func(ctx context.Context) error {
+		client, err := sway.New(ctx)
+		if err != nil {
+			return err
+		}
+		return f(ctx, client)
+	})
+}
+
+// Returns the shell ("xdg_shell", "xwayland") of a window of the process pid
+// or its descendants, from the sway tree. "" if it has none yet.
+func windowShellOf(pid int) (string, error) {
+	var tree *sway.Node
+	err := withSway(func(ctx context.Context, client sway.Client) error {
+		var err error
+		tree, err = client.GetTree(ctx)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	return windowShell(tree, pid), nil
+}
+
+func windowShell(node *sway.Node, pid int) string {
+	if node.PID != nil && node.Shell != nil && launch.DescendsFrom(int(*node.PID), pid) {
+		return *node.Shell
+	}
+	for _, children := range [][]*sway.Node{node.Nodes, node.FloatingNodes} {
+		for _, child := range children {
+			if shell := windowShell(child, pid); shell != "" {
+				return shell
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,99 @@
+package ui
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/gotk3/gotk3/gdk"
+	"github.com/gotk3/gotk3/gtk"
+
+	"github.com/ftphikari/wlaunchpad/internal/config"
+)
+
+// Scale of icons and text on top of the configured sizes, independent of the
+// output scale. Changed with Ctrl+=/Ctrl+-/Ctrl+0 and Ctrl+scroll, kept
+// between sessions.
+var zoom = 1.0
+
+const (
+	minZoom  = 0.5
+	maxZoom  = 3.0
+	zoomStep = 0.25
+)
+
+var zoomProvider *gtk.CssProvider
+
+// Format changes of the saved zoom level, see config.Migrations
+var zoomMigrations = config.Migrations{}
+
+func zoomPath() string {
+	return filepath.Join(config.StateDir(), "zoom")
+}
+
+// Reads the saved zoom level and sets up the style scaling text
+func loadZoom() {
+	zoomProvider, _ = gtk.CssProviderNew()
+	screen, _ := gdk.ScreenGetDefault()
+	// above the user style, font sizes set there are scaled too
+	gtk.AddProviderForScreen(screen, zoomProvider, gtk.STYLE_PROVIDER_PRIORITY_USER)
+
+	saved, err := zoomMigrations.Load(zoomPath())
+	if err != nil && !os.IsNotExist(err) {
+		log.Printf("Couldn't read %s: %s", zoomPath(), err)
+	}
+	var level float64
+	if _, err := fmt.Sscan(saved.Str("", "level", "1"), &level); err == nil {
+		zoom = clampZoom(level)
+	}
+	zoomProvider.LoadFromData(zoomStyle())
+}
+
+func clampZoom(level float64) float64 {
+	if level < minZoom {
+		return minZoom
+	} else if level > maxZoom {
+		return maxZoom
+	}
+	return level
+}
+
+func zoomStyle() string {
+	return fmt.Sprintf("window { font-size: %d%%; }", int(zoom*100))
+}
+
+func zoomedIconSize() int {
+	return int(float64(settings.IconSize) * zoom)
+}
+
+func setZoom(level float64) {
+	level = clampZoom(level)
+	if level == zoom {
+		return
+	}
+	zoom = level
+	log.Printf("Zoom: %d%%\n", int(zoom*100))
+	contents := fmt.Sprintf("level = %g\n", zoom)
+	if err := config.WriteFile(zoomPath(), []byte(contents), 0644); err != nil {
+		log.Printf("Couldn't save %s: %s", zoomPath(), err)
+	}
+
+	zoomProvider.LoadFromData(zoomStyle())
+	forceRelayout()
+	statusLabel.SetText(fmt.Sprintf("Zoom %d%%", int(zoom*100)))
+}
+
+// Handles Ctrl+scroll, reports whether the event was one
+func handleZoomScroll(scroll *gdk.EventScroll) bool {
+	if scroll.State()&gdk.CONTROL_MASK == 0 {
+		return false
+	}
+	switch {
+	case scroll.Direction() == gdk.SCROLL_UP || scroll.DeltaY() < 0:
+		setZoom(zoom + zoomStep)
+	case scroll.Direction() == gdk.SCROLL_DOWN || scroll.DeltaY() > 0:
+		setZoom(zoom - zoomStep)
+	}
+	return true
+}
@@ -0,0 +1,144 @@
+// Package update asks the project's release feed whether a newer version is
+// out
+package update
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ftphikari/wlaunchpad/internal/config"
+)
+
+// FeedURL is the latest release of the project
+const FeedURL = "https://api.github.com/repos/ftphikari/wlaunchpad/releases/latest"
+
+// Interval is how long the answer of the feed is kept before asking again
+const Interval = 24 * time.Hour
+
+// Release is a published version and the page describing it
+type Release struct {
+	Version string `json:"tag_name"`
+	URL     string `json:"html_url"`
+}
+
+// Latest fetches the latest release from the feed at url
+func Latest(ctx context.Context, url string) (Release, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return Release{}, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return Release{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return Release{}, fmt.Errorf("%s: %s", url, resp.Status)
+	}
+	var release Release
+	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
+		return Release{}, err
+	}
+	if release.Version == "" {
+		return Release{}, fmt.Errorf("%s: no version in the answer", url)
+	}
+	return release, nil
+}
+
+// Newer reports whether version a is newer than b. Versions are numbers
+// separated by dots, with an optional "v" in front; anything else, like a
+// development build, is never newer nor older.
+func Newer(a, b string) bool {
+	na, okA := parseVersion(a)
+	nb, okB := parseVersion(b)
+	if !okA || !okB {
+		return false
+	}
+	for i := 0; i < len(na) || i < len(nb); i++ {
+		var x, y int
+		if i < len(na) {
+			x = na[i]
+		}
+		if i < len(nb) {
+			y = nb[i]
+		}
+		if x != y {
+			return x > y
+		}
+	}
+	return false
+}
+
+func parseVersion(v string) ([]int, bool) {
+	v = strings.TrimPrefix(v, "v")
+	if v == "" {
+		return nil, false
+	}
+	var numbers []int
+	for _, part := range strings.Split(v, ".") {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return nil, false
+		}
+		numbers = append(numbers, n)
+	}
+	return numbers, true
+}
+
+// State is the last answer of the feed, kept between runs
+type State struct {
+	Checked time.Time
+	Latest  Release
+}
+
+// Due reports whether the feed should be asked again
+func (s State) Due(now time.Time) bool {
+	return now.Sub(s.Checked) >= Interval || now.Before(s.Checked)
+}
+
+// LoadState reads the state saved at path. A missing file gives a state due
+// for a check.
+func LoadState(path string) (State, error) {
+	var s State
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	} else if err != nil {
+		return s, err
+	}
+	defer f.Close()
+
+	// "<unix time>", "<version>" and "<url>" lines
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return s, err
+	}
+	if len(lines) != 3 {
+		return s, fmt.Errorf("%s: expected 3 lines, got %d", path, len(lines))
+	}
+	t, err := strconv.ParseInt(lines[0], 10, 64)
+	if err != nil {
+		return s, err
+	}
+	s.Checked = time.Unix(t, 0)
+	s.Latest = Release{lines[1], lines[2]}
+	return s, nil
+}
+
+// Save writes the state to path
+func (s State) Save(path string) error {
+	contents := fmt.Sprintf("%d\n%s\n%s\n", s.Checked.Unix(), s.Latest.Version, s.Latest.URL)
+	return config.WriteFile(path, []byte(contents), 0644)
+}
@@ -0,0 +1,79 @@
+package update
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLatest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"tag_name": "v0.4.0", "html_url": "https://example.org/v0.4.0", "draft": false}`)
+	}))
+	defer server.Close()
+
+	release, err := Latest(context.Background(), server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (Release{"v0.4.0", "https://example.org/v0.4.0"}); release != want {
+		t.Errorf("got %+v, expected %+v", release, want)
+	}
+
+	server.Config.Handler = http.NotFoundHandler()
+	if _, err := Latest(context.Background(), server.URL); err == nil {
+		t.Error("expected an error for 404")
+	}
+}
+
+func TestNewer(t *testing.T) {
+	for _, tt := range []struct {
+		a, b string
+		want bool
+	}{
+		{"v0.4.0", "v0.3.2", true},
+		{"v0.10", "v0.9.1", true},
+		{"0.4", "v0.4.0", false},
+		{"v0.3.2", "v0.4.0", false},
+		{"v0.4.0", "dev", false},
+		{"v0.4.0-rc1", "v0.3.0", false},
+	} {
+		if got := Newer(tt.a, tt.b); got != tt.want {
+			t.Errorf("Newer(%q, %q) = %v, expected %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestState(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "update")
+	s, err := LoadState(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Unix(1700000000, 0)
+	if !s.Due(now) {
+		t.Error("a missing state should be due")
+	}
+
+	s = State{now, Release{"v0.4.0", "https://example.org/v0.4.0"}}
+	if err := s.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadState(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !loaded.Checked.Equal(now) || loaded.Latest != s.Latest {
+		t.Errorf("got %+v, expected %+v", loaded, s)
+	}
+	if loaded.Due(now.Add(time.Hour)) {
+		t.Error("checked an hour ago, shouldn't be due")
+	}
+	if !loaded.Due(now.Add(Interval)) {
+		t.Error("checked a day ago, should be due")
+	}
+}
@@ -5,46 +5,21 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"net/url"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"runtime"
+	"strconv"
 	"strings"
 	"syscall"
 	"time"
-	"path/filepath"
-	"runtime"
-
-	"github.com/dlasky/gotk3-layershell/layershell"
-	"github.com/gotk3/gotk3/gdk"
-	"github.com/gotk3/gotk3/glib"
-	"github.com/gotk3/gotk3/gtk"
-)
-
-type desktopEntry struct {
-	DesktopID  string
-	Name       string
-	NameLoc    string
-	Comment    string
-	CommentLoc string
-	Icon       string
-	Exec       string
-	Category   string
-	Terminal   bool
-	NoDisplay  bool
-}
 
-// UI elements
-var (
-	win                    *gtk.Window
-	resultWindow           *gtk.ScrolledWindow
-	searchEntry            *gtk.SearchEntry
-	phrase                 string
-	iconTheme              *gtk.IconTheme
-	appFlowBox             *gtk.FlowBox
-	appSearchResultWrapper *gtk.Box
-	statusLabel            *gtk.Label
-	status                 string
-	desktopEntries         []desktopEntry
-	iconCache              = make(map[string]*gdk.Pixbuf)
+	"github.com/ftphikari/wlaunchpad/internal/config"
+	"github.com/ftphikari/wlaunchpad/internal/entries"
+	"github.com/ftphikari/wlaunchpad/internal/ipc"
+	"github.com/ftphikari/wlaunchpad/internal/launch"
+	"github.com/ftphikari/wlaunchpad/internal/ui"
 )
 
 func defaultStringIfBlank(s, fallback string) string {
@@ -57,327 +32,327 @@ func defaultStringIfBlank(s, fallback string) string {
 	return s
 }
 
-func setUpAppsFlowBox(searchPhrase string) {
-	// this reduces RAM usage significantly for daemon mode
-	// it also MIGHT crash, but did not happen in my testing
-	glib.IdleAdd(func() bool {
-		runtime.GC()
-		return false
-	})
+var settings config.Settings
 
-	if appFlowBox != nil {
-		appFlowBox.GetChildren().Foreach(func(item interface{}) {
-			item.(*gtk.Widget).Destroy()
-		})
-	} else {
-		appFlowBox, _ = gtk.FlowBoxNew()
-		appFlowBox.SetMinChildrenPerLine(*columnsNumber)
-		appFlowBox.SetMaxChildrenPerLine(*columnsNumber)
-		appFlowBox.SetColumnSpacing(*itemSpacing)
-		appFlowBox.SetRowSpacing(*itemSpacing)
-		appFlowBox.SetHomogeneous(true)
-		appFlowBox.SetSelectionMode(gtk.SELECTION_NONE)
-	}
-
-	for _, entry := range desktopEntries {
-		if !(searchPhrase == "" || !entry.NoDisplay && (strings.Contains(strings.ToLower(entry.NameLoc), strings.ToLower(searchPhrase)) ||
-			strings.Contains(strings.ToLower(entry.CommentLoc), strings.ToLower(searchPhrase)) ||
-			strings.Contains(strings.ToLower(entry.Comment), strings.ToLower(searchPhrase)) ||
-			strings.Contains(strings.ToLower(entry.Exec), strings.ToLower(searchPhrase)))) {
-			continue
-		}
-		if !entry.NoDisplay {
-			button, _ := gtk.ButtonNew()
-			button.SetAlwaysShowImage(true)
-
-			pixbuf, ok := iconCache[entry.Icon]
-			if !ok {
-				var err error
-				if entry.Icon != "" {
-					pixbuf, err = createPixbuf(entry.Icon, *iconSize)
-					if err != nil {
-						log.Print(err)
-						pixbuf, err = createPixbuf("image-missing", *iconSize)
-					}
-				}
-				if err != nil {
-					log.Print(err)
-					pixbuf, _ = createPixbuf("unknown", *iconSize)
-				}
-				iconCache[entry.Icon] = pixbuf
-			}
+var showVersion bool
 
-			img, _ := gtk.ImageNewFromPixbuf(pixbuf)
-			button.SetImage(img)
-			button.SetImagePosition(gtk.POS_TOP)
-			name := entry.NameLoc
-			if len(name) > 20 {
-				r := []rune(name)
-				name = string(r[:17])
-				name = fmt.Sprintf("%s…", name)
-			}
-			button.SetLabel(name)
-
-			exec := entry.Exec
-			terminal := entry.Terminal
-			desc := entry.CommentLoc
-			button.Connect("button-release-event", func(btn *gtk.Button, e *gdk.Event) bool {
-				btnEvent := gdk.EventButtonNewFromEvent(e)
-				if btnEvent.Button() == 1 {
-					launch(exec, terminal)
-					return true
-				} else if btnEvent.Button() == 3 {
-					return true
-				}
-				return false
-			})
-			button.Connect("activate", func() {
-				launch(exec, terminal)
-			})
-			button.Connect("enter-notify-event", func() {
-				statusLabel.SetText(desc)
-			})
-			appFlowBox.Add(button)
-		}
+// Flags
+func init() {
+	flag.BoolVar(&settings.Debug, "debug", false, "display debug information")
+	flag.BoolVar(&settings.Daemon, "d", false, "launch in daemon mode")
+	flag.BoolVar(&settings.NoShow, "n", false, "don't show the window on first launch (only if daemon mode is on)")
+	flag.StringVar(&settings.StyleFile, "style", "", "css style file name")
+	flag.StringVar(&settings.TargetOutput, "o", "", "name of the output to display the launchpad on (sway only)")
+	flag.IntVar(&settings.IconSize, "i", 64, "icon size")
+	flag.UintVar(&settings.Columns, "c", 6, "number of columns")
+	flag.UintVar(&settings.Spacing, "s", 20, "icon spacing")
+	flag.StringVar(&settings.ConfigFile, "config", filepath.Join(config.Dir(), "config.toml"), "config file name")
+	flag.StringVar(&settings.Term, "t", defaultStringIfBlank(os.Getenv("TERM"), "foot"), "terminal emulator")
+	flag.BoolVar(&settings.Badges, "badges", false, "show the number of notifications mako shows on app icons, or dunst in the status line")
+	flag.BoolVar(&settings.LauncherEntry, "launcher-entry", false, "show progress and counts reported over com.canonical.Unity.LauncherEntry (needs dbus-monitor)")
+	flag.BoolVar(&settings.DryRun, "dry-run", false, "print commands instead of running them")
+	flag.StringVar(&settings.URL, "url", "", "handle a wlaunchpad://show?q=phrase URL")
+	flag.BoolVar(&settings.Gamepad, "gamepad", false, "navigate with game controllers (needs read access to /dev/input)")
+	flag.BoolVar(&settings.TV, "tv", false, "big picture layout for TVs, implies -gamepad")
+	flag.StringVar(&settings.Category, "category", "", "show only entries of a category, e.g. Game")
+	flag.StringVar(&settings.Launch, "launch", "", "launch a desktop file (path or desktop ID) and exit")
+	flag.BoolVar(&settings.AllDesktops, "all-desktops", false, "ignore OnlyShowIn and NotShowIn, showing entries meant for other desktops")
+	flag.BoolVar(&settings.NoTryExec, "no-tryexec", false, "show entries even if the program in their TryExec key is missing")
+	flag.BoolVar(&settings.OSK, "osk", false, "leave room for on-screen keyboards and show a button summoning one")
+	flag.StringVar(&settings.Backend, "backend", "auto", "display server to use: wayland, x11 or auto (a Wayland socket accepting connections)")
+	flag.DurationVar(&settings.Hibernate, "hibernate", 0, "in daemon mode, free the window after it has been hidden this long, e.g. 30m (0: never)")
+	flag.Var(&settings.AppDirs, "app-dir", "also look for desktop files in this directory, may be repeated")
+	flag.BoolVar(&showVersion, "version", false, "print the version, build information and features compiled in")
+}
+
+func main() {
+	timeStart := time.Now()
+	flag.Parse()
+
+	if showVersion {
+		printBuildInfo()
+		os.Exit(0)
+	}
+	if flag.Arg(0) == "version" {
+		printVersion(flag.Args()[1:])
+		os.Exit(0)
 	}
-	// While moving focus with arrow keys we want buttons to get focus directly
-	appFlowBox.GetChildren().Foreach(func(item interface{}) {
-		item.(*gtk.Widget).SetCanFocus(false)
+
+	// flags set explicitly
+	set := make(map[string]bool)
+	flag.Visit(func(f *flag.Flag) {
+		set[f.Name] = true
 	})
-	resultWindow.ShowAll()
-}
 
-func showWindow() {
-	parseDesktopFiles()
-	searchEntry.SetText("")
-	setUpAppsFlowBox("")
-	resultWindow.GetVAdjustment().SetValue(0)
-	focusFirstItem()
-	win.ShowAll()
-}
+	// subcommands and -launch go by the config file, handing over to a
+	// running instance needs the flags only
+	if flag.Arg(0) != "" || settings.Launch != "" {
+		loadConfig(set)
+	}
 
-func focusFirstItem() {
-	if appFlowBox == nil {
-		return
+	if flag.Arg(0) == "bind-key" {
+		os.Exit(bindKey(flag.Args()[1:]))
+	}
+	if flag.Arg(0) == "resolve-icon" {
+		os.Exit(resolveIcon(flag.Args()[1:]))
+	}
+	if flag.Arg(0) == "theme" {
+		os.Exit(theme(flag.Args()[1:]))
+	}
+	if flag.Arg(0) == "warnings" {
+		os.Exit(printWarnings())
 	}
 
-	b := appFlowBox.GetChildAtIndex(0)
-	if b != nil {
-		button, err := b.GetChild()
-		if err == nil {
-			button.ToWidget().GrabFocus()
+	if !settings.Debug {
+		log.SetOutput(io.Discard)
+	}
+
+	if settings.Launch != "" {
+		if err := launchFile(settings.Launch); err != nil {
+			fmt.Fprintf(os.Stderr, "Couldn't launch %s: %s\n", settings.Launch, err)
+			os.Exit(1)
 		}
+		os.Exit(0)
 	}
-}
 
-// Flags
-var (
-	debug         = flag.Bool("debug", false, "display debug information")
-	daemon        = flag.Bool("d", false, "launch in daemon mode")
-	noshow        = flag.Bool("n", false, "don't show the window on first launch (only if daemon mode is on)")
-	styleFile     = flag.String("style", "", "css style file name")
-	targetOutput  = flag.String("o", "", "name of the output to display the launchpad on (sway only)")
-	iconSize      = flag.Int("i", 64, "icon size")
-	columnsNumber = flag.Uint("c", 6, "number of columns")
-	itemSpacing   = flag.Uint("s", 20, "icon spacing")
-	term          = flag.String("t", defaultStringIfBlank(os.Getenv("TERM"), "foot"), "terminal emulator")
-)
+	var request ipc.Request
+	var err error
+	if settings.URL != "" {
+		request, err = ipc.ParseURL(settings.URL)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid URL: %s\n", err)
+			os.Exit(1)
+		}
+	} else if settings.Category != "" {
+		request, err = ipc.ParseRequest("show?" + url.Values{"category": {settings.Category}}.Encode())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid category: %s\n", err)
+			os.Exit(1)
+		}
+	}
 
-func main() {
-	timeStart := time.Now()
-	flag.Parse()
+	// Appearance given to a second invocation is applied by the running one
+	var configure ipc.Request
+	if set["c"] || set["i"] || set["s"] || set["tv"] {
+		configure = ipc.Request{Action: "configure", Args: url.Values{}}
+		if settings.TV {
+			tvDefaults(set, nil)
+		}
+		configure.Args.Set("c", strconv.FormatUint(uint64(settings.Columns), 10))
+		configure.Args.Set("i", strconv.Itoa(settings.IconSize))
+		configure.Args.Set("s", strconv.FormatUint(uint64(settings.Spacing), 10))
+	}
 
-	if !*debug {
-		log.SetOutput(io.Discard)
+	// We want the same key/mouse binding to turn the dock off: hand over to the
+	// running instance and exit. This is the path of every keybinding press, so
+	// nothing up to here touches GTK or Wayland, or reads the config file.
+	lockFilePath := ipc.LockFilePath()
+	lockFile, err := ipc.CreateLockFile(lockFilePath)
+	if err != nil {
+		handOver(lockFilePath, request, configure)
+		log.Printf("Handed over to the running instance in %v ms\n", time.Since(timeStart).Milliseconds())
+		os.Exit(0)
+	}
+	defer lockFile.Close()
+
+	cfg := loadConfig(set)
+	if settings.Debug {
+		// -debug may come from the config file
+		log.SetOutput(os.Stderr)
+	}
+	if settings.Backend != "auto" && settings.Backend != "wayland" && settings.Backend != "x11" {
+		fmt.Fprintf(os.Stderr, "Invalid backend %q, expected wayland, x11 or auto\n", settings.Backend)
+		os.Exit(2)
 	}
 
 	// Gentle SIGTERM handler thanks to reiki4040 https://gist.github.com/reiki4040/be3705f307d3cd136e85
 	signalChan := make(chan os.Signal, 1)
-	signal.Notify(signalChan, syscall.SIGTERM, syscall.SIGUSR1)
+	signal.Notify(signalChan, syscall.SIGTERM, syscall.SIGUSR1, syscall.SIGHUP)
 	go func() {
 		for {
 			s := <-signalChan
-			if s == syscall.SIGTERM || (s == syscall.SIGUSR1 && !*daemon) {
+			if s == syscall.SIGTERM || (s == syscall.SIGUSR1 && !settings.Daemon) {
 				log.Println("SIGTERM or SIGUSR1 received, exiting..")
-				gtk.MainQuit()
+				ui.Quit()
 			} else if s == syscall.SIGUSR1 {
-				log.Println("SIGUSR1 received, toggling..", )
-				glib.IdleAdd(func() bool {
-					if win.GetVisible() {
-						win.Hide()
-					} else {
-						showWindow()
-					}
-					return false
+				log.Println("SIGUSR1 received, toggling..")
+				ui.Toggle()
+			} else if s == syscall.SIGHUP {
+				log.Println("SIGHUP received, reloading the config..")
+				ui.Reload(func() config.Config {
+					return loadConfig(set)
 				})
 			}
 		}
 	}()
 
-	// We want the same key/mouse binding to turn the dock off: kill the running instance and exit.
-	lockFilePath := filepath.Join(tempDir(), "wlaunchpad.lock")
-	lockFile, err := createLockFile(lockFilePath)
-	if err != nil {
-		pid, err := getLockFilePid(lockFilePath)
-		if err == nil {
-			log.Println("Running instance found, sending SIGUSR1 and exiting…")
-			syscall.Kill(pid, syscall.SIGUSR1)
-		}
-		os.Exit(0)
+	if err := ipc.Serve(ipc.SocketPath(), ui.Handle); err != nil {
+		log.Printf("Couldn't listen for requests: %s", err)
 	}
-	defer lockFile.Close()
+	defer os.Remove(ipc.SocketPath())
 
-	// USER INTERFACE
-	gtk.Init(nil)
+	ui.Init(&settings, cfg)
+	if request.Action != "" {
+		ui.Handle(request)
+	}
 
-	cssProvider, _ := gtk.CssProviderNew()
-	if *styleFile != "" {
-		err = cssProvider.LoadFromPath(*styleFile)
-		if err != nil {
-			log.Printf("ERROR: %s css file not found or erroneous. Using GTK styling.\n", *styleFile)
-			log.Printf("%s\n", err)
-		} else {
-			log.Printf("Using style from %s\n", *styleFile)
-			screen, _ := gdk.ScreenGetDefault()
-			gtk.AddProviderForScreen(screen, cssProvider, gtk.STYLE_PROVIDER_PRIORITY_APPLICATION)
+	t := time.Now()
+	log.Printf("UI created in %v ms. Thank you for your patience.\n", t.Sub(timeStart).Milliseconds())
+	ui.Main()
+}
+
+// Loads the config file and sets the flags not set on the command line, listed
+// in set, from it. Flags it no longer sets go back to their defaults, for
+// reloads.
+func loadConfig(set map[string]bool) config.Config {
+	flag.VisitAll(func(f *flag.Flag) {
+		if !set[f.Name] {
+			f.Value.Set(f.DefValue)
 		}
-	}
+	})
 
-	win, err = gtk.WindowNew(gtk.WINDOW_TOPLEVEL)
-	if err != nil {
-		log.Fatal("Unable to create window:", err)
+	cfg, err := config.Load(settings.ConfigFile)
+	if err != nil && !os.IsNotExist(err) {
+		fmt.Fprintf(os.Stderr, "%s config file erroneous: %s\n", settings.ConfigFile, err)
+	}
+	configured, errs := cfg.ApplyFlags(flag.CommandLine, set)
+	for _, err := range errs {
+		fmt.Fprintf(os.Stderr, "%s: %s\n", settings.ConfigFile, err)
 	}
 
-	if wayland() {
-		layershell.InitForWindow(win)
+	if settings.TV {
+		tvDefaults(set, configured)
+	}
+	entries.ExtraAppDirs = settings.AppDirs
+	return cfg
+}
 
-		var output2mon map[string]*gdk.Monitor
-		if *targetOutput != "" {
-			// We want to assign layershell to a monitor, but we only know the output name!
-			output2mon, err = mapOutputs()
-			if err == nil {
-				monitor := output2mon[*targetOutput]
-				layershell.SetMonitor(win, monitor)
+// Bigger defaults for -tv, unless set explicitly or in the config file
+func tvDefaults(set, configured map[string]bool) {
+	if !set["i"] && !configured["i"] {
+		settings.IconSize = 128
+	}
+	if !set["c"] && !configured["c"] {
+		settings.Columns = 5
+	}
+	if !set["s"] && !configured["s"] {
+		settings.Spacing = 40
+	}
+	if !set["gamepad"] && !configured["gamepad"] {
+		settings.Gamepad = true
+	}
+}
 
-			} else {
-				log.Printf("%s", err)
-			}
-		}
+// Prints the version, and with -features the capabilities compiled in
+func printVersion(args []string) {
+	fs := flag.NewFlagSet("version", flag.ExitOnError)
+	features := fs.Bool("features", false, "list the optional features compiled in")
+	fs.Parse(args)
 
-		layershell.SetAnchor(win, layershell.LAYER_SHELL_EDGE_BOTTOM, true)
-		layershell.SetAnchor(win, layershell.LAYER_SHELL_EDGE_TOP, true)
-		layershell.SetAnchor(win, layershell.LAYER_SHELL_EDGE_LEFT, true)
-		layershell.SetAnchor(win, layershell.LAYER_SHELL_EDGE_RIGHT, true)
-		layershell.SetLayer(win, layershell.LAYER_SHELL_LAYER_OVERLAY)
-		layershell.SetExclusiveZone(win, -1)
-		layershell.SetKeyboardMode(win, layershell.LAYER_SHELL_KEYBOARD_MODE_EXCLUSIVE)
+	if !*features {
+		fmt.Println(config.Version)
+		return
 	}
+	printBuildInfo()
+}
 
-	win.Connect("destroy", func() {
-		if *daemon {
-			win.Hide()
-		} else {
-			gtk.MainQuit()
-		}
-	})
+// Prints the version and how the binary was built, for bug reports
+func printBuildInfo() {
+	fmt.Printf("wlaunchpad %s\n", config.Version)
+	fmt.Printf("commit: %s\n", config.Commit)
+	fmt.Printf("built: %s with %s\n", config.BuildDate, runtime.Version())
+	for _, kind := range config.FeatureKinds() {
+		fmt.Printf("%s: %s\n", kind, strings.Join(config.Features(kind), ", "))
+	}
+}
 
-	win.Connect("key-press-event", func(window *gtk.Window, event *gdk.Event) bool {
-		key := &gdk.EventKey{Event: event}
-		switch key.KeyVal() {
-		case gdk.KEY_Escape:
-			s, _ := searchEntry.GetText()
-			if s != "" {
-				searchEntry.GrabFocus()
-				searchEntry.SetText("")
-			} else {
-				if *daemon {
-					win.Hide()
-				} else {
-					gtk.MainQuit()
-				}
-			}
-			return false
-		case gdk.KEY_downarrow, gdk.KEY_Up, gdk.KEY_Down, gdk.KEY_Left, gdk.KEY_Right, gdk.KEY_Tab,
-			gdk.KEY_Return, gdk.KEY_Page_Up, gdk.KEY_Page_Down, gdk.KEY_Home, gdk.KEY_End:
-			return false
-
-		default:
-			if !searchEntry.IsFocus() {
-				searchEntry.GrabFocusWithoutSelecting()
-			}
-			return false
+// Prints the file the grid would show for an icon name or desktop ID, returns
+// the exit status
+func resolveIcon(args []string) int {
+	if len(args) < 1 || len(args) > 2 {
+		fmt.Fprintln(os.Stderr, "Usage: wlaunchpad resolve-icon NAME|DESKTOP-ID [SIZE]")
+		return 2
+	}
+	size := settings.IconSize
+	if len(args) == 2 {
+		var err error
+		if size, err = strconv.Atoi(args[1]); err != nil || size <= 0 {
+			fmt.Fprintf(os.Stderr, "Invalid size %q\n", args[1])
+			return 2
 		}
-	})
-
-	/*
-		In case someone REALLY needed to use X11 - for some stupid Zoom meeting or something, this allows
-		the drawer to behave properly on Openbox, and possibly somewhere else. For sure not on i3.
-		This feature is not really supported and will stay undocumented.
-	*/
-	if !wayland() {
-		log.Println("Not Wayland, oh really?")
-		win.SetDecorated(false)
-		win.Maximize()
-	}
-	// Set up UI
-	iconTheme, err = gtk.IconThemeGetDefault()
+	}
+	path, err := ui.ResolveIcon(args[0], size)
 	if err != nil {
-		log.Fatal("Couldn't get default theme: ", err)
+		fmt.Fprintln(os.Stderr, err)
+		return 1
 	}
+	fmt.Println(path)
+	return 0
+}
 
-	outerVBox, _ := gtk.BoxNew(gtk.ORIENTATION_VERTICAL, 0)
-	win.Add(outerVBox)
-
-	searchBoxWrapper, _ := gtk.BoxNew(gtk.ORIENTATION_HORIZONTAL, 0)
-	outerVBox.PackStart(searchBoxWrapper, false, false, 10)
+// Prints what the running instance's last scan skipped, as JSON, returns the
+// exit status
+func printWarnings() int {
+	warnings, err := ipc.Query(ipc.SocketPath(), ipc.Request{Action: "warnings"})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Couldn't ask the running instance: %s\n", err)
+		return 1
+	}
+	fmt.Println(warnings)
+	return 0
+}
 
-	searchEntry, _ = gtk.SearchEntryNew()
-	searchEntry.SetPlaceholderText("Type to search")
-	searchEntry.Connect("search-changed", func() {
-		phrase, _ = searchEntry.GetText()
-		if len(phrase) > 0 {
-			setUpAppsFlowBox(phrase)
-		} else {
-			setUpAppsFlowBox("")
+// Passes the request, or else the settings, to the running instance. Without
+// either, or if the socket doesn't answer, the instance gets toggled.
+func handOver(lockFilePath string, request, configure ipc.Request) {
+	if request.Action != "" {
+		err := ipc.Send(ipc.SocketPath(), request)
+		if err == nil {
+			return
 		}
-		focusFirstItem()
-	})
-	searchEntry.SetMaxWidthChars(30)
-	searchBoxWrapper.PackStart(searchEntry, true, false, 0)
-
-	resultWindow, _ = gtk.ScrolledWindowNew(nil, nil)
-	resultWindow.SetEvents(int(gdk.ALL_EVENTS_MASK))
-	resultWindow.SetPolicy(gtk.POLICY_AUTOMATIC, gtk.POLICY_AUTOMATIC)
-	outerVBox.PackStart(resultWindow, true, true, 10)
-
-	resultsWrapper, _ := gtk.BoxNew(gtk.ORIENTATION_VERTICAL, 0)
-	resultWindow.Add(resultsWrapper)
-
-	appSearchResultWrapper, _ = gtk.BoxNew(gtk.ORIENTATION_VERTICAL, 0)
-	resultsWrapper.PackStart(appSearchResultWrapper, false, false, 0)
-
-	status = parseDesktopFiles()
-	setUpAppsFlowBox("")
-
-	hWrapper, _ := gtk.BoxNew(gtk.ORIENTATION_HORIZONTAL, 0)
-	appSearchResultWrapper.PackStart(hWrapper, false, false, 0)
-	hWrapper.PackStart(appFlowBox, true, false, 0)
-
-	placeholder, _ := gtk.BoxNew(gtk.ORIENTATION_VERTICAL, 0)
-	resultsWrapper.PackStart(placeholder, true, true, 0)
-	placeholder.SetSizeRequest(20, 20)
-
-	statusLineWrapper, _ := gtk.BoxNew(gtk.ORIENTATION_HORIZONTAL, 0)
-	outerVBox.PackStart(statusLineWrapper, false, false, 10)
-	statusLabel, _ = gtk.LabelNew(status)
-	statusLineWrapper.PackStart(statusLabel, true, false, 0)
+		log.Printf("Couldn't send the request: %s", err)
+	} else if configure.Action != "" {
+		err := ipc.Send(ipc.SocketPath(), configure)
+		if err == nil {
+			return
+		}
+		log.Printf("Couldn't send the settings: %s", err)
+	}
+	pid, err := ipc.LockFilePid(lockFilePath)
+	if err == nil {
+		log.Println("Running instance found, sending SIGUSR1 and exiting…")
+		syscall.Kill(pid, syscall.SIGUSR1)
+	}
+}
 
-	if !*daemon || !*noshow {
-		focusFirstItem()
-		win.ShowAll()
+// Launches a desktop file given by path or desktop ID the way the grid would,
+// without showing any UI
+func launchFile(file string) error {
+	path, id := file, filepath.Base(file)
+	if !strings.Contains(file, "/") {
+		var err error
+		path, err = entries.FindFile(file)
+		if err != nil {
+			return err
+		}
+		// the ID of files in subdirectories isn't their name
+		id = strings.TrimSuffix(file, ".desktop") + ".desktop"
+	}
+	entry, err := entries.ParseFile(id, path)
+	if err != nil {
+		return err
+	}
+	if err := entries.Validate(entry); err != nil {
+		return fmt.Errorf("%s: %s", path, err)
 	}
 
-	t := time.Now()
-	log.Printf("UI created in %v ms. Thank you for your patience.\n", t.Sub(timeStart).Milliseconds())
-	gtk.Main()
+	cmd := launch.Command(entry.Exec, launch.Entry{Name: entry.NameLoc, Icon: entry.Icon, Path: entry.Path, Dir: entry.WorkingDir}, entry.Terminal, settings.Term)
+	if entry.PrefersNonDefaultGPU {
+		launch.UseNonDefaultGPU(cmd, launch.ProbeGPUs("/sys"))
+	}
+	if settings.DryRun {
+		fmt.Print(launch.Describe(cmd))
+		return nil
+	}
+	return cmd.Start()
 }
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ftphikari/wlaunchpad/internal/ipc"
+	"github.com/ftphikari/wlaunchpad/internal/ui"
+)
+
+// How often theme preview looks for changes
+const previewInterval = 500 * time.Millisecond
+
+// Runs the theme subcommand, returns the exit status
+func theme(args []string) int {
+	if len(args) != 1 || args[0] != "preview" {
+		fmt.Fprintln(os.Stderr, "Usage: wlaunchpad theme preview")
+		return 2
+	}
+	if err := ipc.Send(ipc.SocketPath(), ipc.Request{Action: "show"}); err != nil {
+		fmt.Fprintf(os.Stderr, "No running wlaunchpad to preview the theme in: %s\n", err)
+		return 1
+	}
+
+	files := []string{ui.ThemePath()}
+	if settings.StyleFile != "" {
+		files = append(files, settings.StyleFile)
+	}
+	fmt.Printf("Watching %v, Ctrl+C to stop\n", files)
+	last := lastChange(files)
+	for range time.Tick(previewInterval) {
+		changed := lastChange(files)
+		if changed.Equal(last) {
+			continue
+		}
+		last = changed
+		if err := ui.CheckTheme(); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", ui.ThemePath(), err)
+			continue
+		}
+		if err := ipc.Send(ipc.SocketPath(), ipc.Request{Action: "style"}); err != nil {
+			fmt.Fprintf(os.Stderr, "Couldn't reload the style: %s\n", err)
+			return 1
+		}
+		fmt.Printf("%s reloaded\n", changed.Format("15:04:05"))
+	}
+	return 0
+}
+
+// Returns the latest modification time of the files that exist
+func lastChange(files []string) time.Time {
+	var last time.Time
+	for _, file := range files {
+		if info, err := os.Stat(file); err == nil && info.ModTime().After(last) {
+			last = info.ModTime()
+		}
+	}
+	return last
+}